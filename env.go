@@ -0,0 +1,23 @@
+package logboek
+
+import (
+	"os"
+
+	"github.com/werf/logboek/internal/settings"
+	"github.com/werf/logboek/pkg/types"
+)
+
+// ConfigureFromEnv configures the logger with the environment variables. It is
+// applied to the default logger automatically, ignoring the invalid variables,
+// so call it explicitly to report them:
+//
+//	LOGBOEK_LEVEL        fatal, error, warn, default, info, debug, trace or a number
+//	LOGBOEK_COLOR        auto, on or off
+//	LOGBOEK_WIDTH        width of the output
+//	LOGBOEK_FORMAT       pretty or plain
+//	LOGBOEK_PREFIX_TIME  true or false
+//	LOGBOEK_BORDERS      true or false
+//	LOGBOEK_COMPONENTS   component levels, e.g. registry=debug,git=info,*=default
+func ConfigureFromEnv(logger types.LoggerInterface) error {
+	return settings.ApplyEnv(logger, os.LookupEnv)
+}
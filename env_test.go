@@ -0,0 +1,36 @@
+package logboek
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestConfigureFromEnv_silentImport(t *testing.T) {
+	if os.Getenv("LOGBOEK_TEST_IMPORT") == "1" {
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestConfigureFromEnv_silentImport$")
+	cmd.Env = append(os.Environ(), "LOGBOEK_TEST_IMPORT=1", "LOGBOEK_LEVEL=loud")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("%s: %s", err, stderr.String())
+	}
+
+	if stderr.Len() != 0 {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", "", stderr.String())
+	}
+}
+
+func TestConfigureFromEnv_error(t *testing.T) {
+	t.Setenv("LOGBOEK_LEVEL", "loud")
+
+	var out bytes.Buffer
+	if err := ConfigureFromEnv(NewLogger(&out, &out)); err == nil {
+		t.Errorf("expected error for the invalid level")
+	}
+}
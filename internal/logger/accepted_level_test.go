@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/werf/logboek/pkg/level"
+	"github.com/werf/logboek/pkg/types"
+)
+
+func TestLogger_DoWithAcceptedLevel(t *testing.T) {
+	var out, subOut bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+
+	if err := l.SetComponentLevels("registry=error"); err != nil {
+		t.Fatal(err)
+	}
+
+	var subLogger types.LoggerInterface
+	l.DoWithAcceptedLevel(level.Debug, func() {
+		l.Debug().LogLn("debug")
+		l.Component("registry").Debug().LogLn("registry debug")
+
+		l.DoWithAcceptedLevel(level.Error, func() {
+			l.Warn().LogLn("hidden")
+		})
+
+		subLogger = l.NewSubLogger(&subOut, &subOut)
+		subLogger.Streams().DisableStyle()
+		subLogger.Debug().LogLn("sub-logger debug")
+	})
+
+	l.Debug().LogLn("hidden")
+	subLogger.Debug().LogLn("hidden")
+
+	err := l.DoErrorWithAcceptedLevel(level.Info, func() error {
+		l.Info().LogLn("info")
+		return errors.New("error")
+	})
+	if err == nil {
+		t.Errorf("expected error")
+	}
+
+	if expected := "debug\nregistry debug\ninfo\n"; out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+
+	if expected := "sub-logger debug\n"; subOut.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, subOut.String())
+	}
+}
+
+func TestLogger_DoWithAcceptedLevel_concurrent(t *testing.T) {
+	l := NewLogger(&bytes.Buffer{}, &bytes.Buffer{})
+
+	debugStarted, errorStarted, debugDone := make(chan struct{}), make(chan struct{}), make(chan struct{})
+	var levelAfterDebugDone level.Level
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		defer close(debugDone)
+
+		l.DoWithAcceptedLevel(level.Debug, func() {
+			close(debugStarted)
+			<-errorStarted
+		})
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		<-debugStarted
+		l.DoWithAcceptedLevel(level.Error, func() {
+			close(errorStarted)
+			<-debugDone
+			levelAfterDebugDone = l.AcceptedLevel()
+		})
+	}()
+
+	wg.Wait()
+
+	if levelAfterDebugDone != level.Error {
+		t.Errorf("\n[EXPECTED]: %v\n[GOT]: %v", level.Error, levelAfterDebugDone)
+	}
+
+	if l.AcceptedLevel() != level.Default {
+		t.Errorf("\n[EXPECTED]: %v\n[GOT]: %v", level.Default, l.AcceptedLevel())
+	}
+}
+
+func TestLogBlockOptions_AcceptedLevel(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+	l.Streams().DisableLogProcessBorder()
+
+	l.LogBlock("block").Options(func(options types.LogBlockOptionsInterface) {
+		options.AcceptedLevel(level.Debug)
+	}).Do(func() {
+		l.Debug().LogLn("block debug")
+	})
+
+	l.LogProcess("process").Options(func(options types.LogProcessOptionsInterface) {
+		options.AcceptedLevel(level.Error)
+		options.WithoutElapsedTime()
+	}).Do(func() {
+		l.LogLn("hidden")
+	})
+
+	l.Debug().LogLn("hidden")
+
+	if expected := "block\nblock debug\nblock\n\nprocess\nprocess\n"; out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+}
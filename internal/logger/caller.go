@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"strings"
+)
+
+// moduleDir is the directory of the logboek module, whose frames are skipped
+// when looking for the caller. The frame file paths are slash-separated.
+var moduleDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return path.Dir(path.Dir(path.Dir(file))) + "/"
+}()
+
+// callerLocation returns the file:line of the first caller outside of the
+// logboek module; the test files of the module are not skipped.
+func callerLocation() string {
+	pc := make([]uintptr, 32)
+	n := runtime.Callers(3, pc)
+	frames := runtime.CallersFrames(pc[:n])
+
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.File, moduleDir) || strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", path.Base(frame.File), frame.Line)
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/werf/logboek/pkg/level"
+)
+
+func TestLogger_CaptureStdFDs(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := NewLogger(&out, &errOut)
+	l.Streams().DisableStyle()
+
+	err := l.LogProcess("process").DoError(func() error {
+		restore, err := l.CaptureStdFDs()
+		if err != nil {
+			return err
+		}
+
+		_, _ = unix.Write(unix.Stdout, []byte("stdout line\n"))
+		_, _ = fmt.Fprint(os.Stderr, "stderr line")
+
+		return restore()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "│ stdout line\n"; !strings.Contains(out.String(), expected) {
+		t.Errorf("\n[EXPECTED]: %q in\n[GOT]: %q", expected, out.String())
+	}
+
+	if expected := "│ stderr line\n"; !strings.Contains(errOut.String(), expected) {
+		t.Errorf("\n[EXPECTED]: %q in\n[GOT]: %q", expected, errOut.String())
+	}
+}
+
+func TestLogger_CaptureStdFDs_loggerOwnOutput(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	savedStdout, err := unix.Dup(unix.Stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unix.Close(savedStdout)
+
+	if err := unix.Dup2(int(w.Fd()), unix.Stdout); err != nil {
+		t.Fatal(err)
+	}
+	defer unix.Dup2(savedStdout, unix.Stdout)
+
+	var errOut bytes.Buffer
+	l := NewLogger(os.Stdout, &errOut)
+	l.Streams().DisableStyle()
+	l.Streams().SetPrefix("l: ")
+
+	other := NewLogger(os.Stdout, &errOut)
+	other.Streams().DisableStyle()
+
+	restore, err := l.CaptureStdFDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.Default().LogLn("own line")
+	other.Default().LogLn("other logger line")
+	_, _ = unix.Write(unix.Stdout, []byte("captured line\n"))
+
+	if err := restore(); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = unix.Dup2(savedStdout, unix.Stdout)
+	_ = w.Close()
+
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r)
+
+	if expected := "l: own line\nother logger line\nl: captured line\n"; buf.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, buf.String())
+	}
+}
+
+func TestLogger_CaptureStdFDs_childHoldingFd(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := NewLogger(&out, &errOut)
+	l.Streams().DisableStyle()
+
+	restore, err := l.CaptureStdFDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("sh", "-c", "echo child line; sleep 10")
+	cmd.Stdout = os.Stdout
+	if err := cmd.Start(); err != nil {
+		_ = restore()
+		t.Skipf("unable to start child process: %s", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	start := time.Now()
+	if err := restore(); err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed := time.Since(start); elapsed > captureDrainTimeout+time.Second {
+		t.Errorf("restore has been blocked for %s", elapsed)
+	}
+}
+
+func TestLogger_CaptureStdFDs_restoreTwice(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := NewLogger(&out, &errOut)
+
+	restore, err := l.CaptureStdFDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := restore(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := restore(); err != nil {
+		t.Errorf("unexpected error on the second restore: %s", err)
+	}
+}
+
+func TestLogger_captureFd_savedFdCloseOnExec(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+
+	capture, err := l.captureFd(unix.Stdout, l.getLevelManager(level.Default))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = capture.restore() }()
+
+	flags, err := unix.FcntlInt(capture.savedFile.Fd(), unix.F_GETFD, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if flags&unix.FD_CLOEXEC == 0 {
+		t.Errorf("the saved descriptor is inherited by child processes")
+	}
+}
+
+type stdoutWrapper struct {
+	w io.Writer
+}
+
+func (w stdoutWrapper) Write(data []byte) (int, error) {
+	return w.w.Write(data)
+}
+
+type stdoutFdWrapper struct {
+	stdoutWrapper
+}
+
+func (w stdoutFdWrapper) Fd() uintptr {
+	return os.Stdout.Fd()
+}
+
+// TestLogger_CaptureStdFDs_wrappedStdout checks that a logger writing to a
+// wrapped os.Stdout is bypassed only if the wrapper exposes the descriptor.
+func TestLogger_CaptureStdFDs_wrappedStdout(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	savedStdout, err := unix.Dup(unix.Stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unix.Close(savedStdout)
+
+	if err := unix.Dup2(int(w.Fd()), unix.Stdout); err != nil {
+		t.Fatal(err)
+	}
+	defer unix.Dup2(savedStdout, unix.Stdout)
+
+	var errOut bytes.Buffer
+	l := NewLogger(os.Stdout, &errOut)
+	l.Streams().DisableStyle()
+	l.Streams().SetPrefix("l: ")
+
+	wrapped := NewLogger(stdoutWrapper{os.Stdout}, &errOut)
+	wrapped.Streams().DisableStyle()
+
+	wrappedWithFd := NewLogger(stdoutFdWrapper{stdoutWrapper{os.Stdout}}, &errOut)
+	wrappedWithFd.Streams().DisableStyle()
+
+	restore, err := l.CaptureStdFDs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrapped.Default().LogLn("wrapped line")
+	wrappedWithFd.Default().LogLn("wrapped line with fd")
+
+	if err := restore(); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = unix.Dup2(savedStdout, unix.Stdout)
+	_ = w.Close()
+
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r)
+
+	// The captured line is logged asynchronously.
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	sort.Strings(lines)
+
+	if expected := []string{"l: wrapped line", "wrapped line with fd"}; !reflect.DeepEqual(expected, lines) {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, lines)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package logger
+
+import "errors"
+
+// CaptureStdFDs is not supported on this platform.
+func (l *Logger) CaptureStdFDs() (func() error, error) {
+	return nil, errors.New("capturing stdout and stderr file descriptors is not supported on this platform")
+}
@@ -0,0 +1,141 @@
+//go:build unix
+
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/werf/logboek/internal/stream"
+	"github.com/werf/logboek/pkg/level"
+)
+
+// CaptureStdFDs redirects the process stdout and stderr file descriptors
+// through pipes into the Default() and Error() proxy streams. The streams of
+// all loggers writing to these descriptors, including the default logger, keep
+// writing to the real ones. The returned function restores the descriptors and
+// waits for the captured data to be logged. Child processes that inherited the
+// descriptors keep the pipes open: restore waits for them up to
+// captureDrainTimeout, and their remaining output is logged in the background
+// until they close the descriptors. Only the first call of restore restores
+// the descriptors, the following calls return its result.
+//
+// The streams writing to the descriptors are recognized by the Fd method of
+// their writers. A writer wrapping os.Stdout or os.Stderr without exposing the
+// Fd method, e.g. a bufio.Writer, is not recognized: the output of its logger
+// is captured, and if it is the capturing logger, its output is captured and
+// logged again without end. Such a writer must implement Fd() uintptr
+// returning the descriptor of the wrapped file.
+func (l *Logger) CaptureStdFDs() (func() error, error) {
+	var captures []*fdCapture
+	var restoreOnce sync.Once
+	var restoreErr error
+	restore := func() error {
+		restoreOnce.Do(func() {
+			var errs []error
+			for i := len(captures) - 1; i >= 0; i-- {
+				if err := captures[i].restore(); err != nil {
+					errs = append(errs, err)
+				}
+			}
+
+			l.Flush()
+
+			restoreErr = errors.Join(errs...)
+		})
+
+		return restoreErr
+	}
+
+	for _, c := range []struct {
+		fd  int
+		lvl level.Level
+	}{
+		{fd: unix.Stdout, lvl: level.Default},
+		{fd: unix.Stderr, lvl: level.Error},
+	} {
+		capture, err := l.captureFd(c.fd, l.getLevelManager(c.lvl))
+		if err != nil {
+			_ = restore()
+			return nil, fmt.Errorf("unable to capture fd %d: %w", c.fd, err)
+		}
+
+		captures = append(captures, capture)
+	}
+
+	return restore, nil
+}
+
+const captureDrainTimeout = time.Second
+
+type fdCapture struct {
+	fd         int
+	savedFile  *os.File
+	unredirect func()
+	copyDone   chan struct{}
+}
+
+func (l *Logger) captureFd(fd int, m *Manager) (*fdCapture, error) {
+	savedFd, err := unix.FcntlInt(uintptr(fd), unix.F_DUPFD_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	savedFile := os.NewFile(uintptr(savedFd), fmt.Sprintf("/dev/fd/%d", fd))
+
+	pipeReader, pipeWriter, err := os.Pipe()
+	if err != nil {
+		_ = savedFile.Close()
+		return nil, err
+	}
+
+	c := &fdCapture{
+		fd:         fd,
+		savedFile:  savedFile,
+		unredirect: stream.RedirectFd(fd, savedFile),
+		copyDone:   make(chan struct{}),
+	}
+
+	if err := unix.Dup2(int(pipeWriter.Fd()), fd); err != nil {
+		_ = pipeWriter.Close()
+		c.unredirect()
+		_ = pipeReader.Close()
+		_ = savedFile.Close()
+		return nil, err
+	}
+	_ = pipeWriter.Close()
+
+	go func() {
+		defer close(c.copyDone)
+		defer pipeReader.Close()
+
+		_, _ = io.Copy(m.Stream(), pipeReader)
+		m.flushLine()
+	}()
+
+	return c, nil
+}
+
+// restore points the descriptor back to the saved one, which closes the last
+// write end of the pipe held by the process, so the copying ends after the
+// captured data is read, unless child processes still hold the write end.
+func (c *fdCapture) restore() error {
+	err := unix.Dup2(int(c.savedFile.Fd()), c.fd)
+	if err == nil {
+		select {
+		case <-c.copyDone:
+		case <-time.After(captureDrainTimeout):
+		}
+	}
+
+	c.unredirect()
+	_ = c.savedFile.Close()
+
+	return err
+}
@@ -0,0 +1,161 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/werf/logboek/pkg/clock"
+	"github.com/werf/logboek/pkg/level"
+	"github.com/werf/logboek/pkg/types"
+)
+
+func TestLogger_RegisterChannel(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := NewLogger(&out, &errOut)
+	l.Streams().DisableStyle()
+
+	verbose := l.RegisterChannel("verbose", level.Debug, types.ChannelOptions{})
+	l.RegisterChannel("audit", level.Info, types.ChannelOptions{Route: types.ErrStreamRoute})
+
+	verbose.LogLn("hidden")
+	l.SetAcceptedLevel(level.Debug)
+	verbose.LogLn("verbose")
+	l.Trace().LogLn("hidden")
+
+	audit, ok := l.With("k", "v").Channel("audit")
+	if !ok {
+		t.Fatalf("expected the channel to be registered in the derived logger")
+	}
+	audit.LogLn("audit")
+
+	if expected := "verbose\n"; out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+
+	if expected := "audit k=v\n"; errOut.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, errOut.String())
+	}
+
+	if m, ok := l.Channel("unknown"); ok || m != nil {
+		t.Errorf("expected no manager for the unregistered channel")
+	}
+
+	if _, ok := l.NewSubLogger(&out, &errOut).Channel("verbose"); !ok {
+		t.Errorf("expected the channel to be registered in the sub-logger")
+	}
+}
+
+func TestLogger_RegisterChannel_betweenLevels(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := NewLogger(&out, &errOut)
+	l.Streams().DisableStyle()
+
+	verboseLevel := level.Info + level.Step/2
+	verbose := l.RegisterChannel("verbose", verboseLevel, types.ChannelOptions{})
+
+	l.SetAcceptedLevel(level.Info)
+	verbose.LogLn("hidden at info")
+
+	l.SetAcceptedLevel(verboseLevel)
+	l.Info().LogLn("info")
+	verbose.LogLn("verbose")
+	l.Debug().LogLn("hidden at verbose")
+
+	if expected := "info\nverbose\n"; out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+
+	if lvl, err := level.ParseLevel(verboseLevel.String()); err != nil || lvl != verboseLevel {
+		t.Errorf("\n[EXPECTED]: %v\n[GOT]: %v (%v)", verboseLevel, lvl, err)
+	}
+}
+
+func TestLogger_RegisterChannel_again(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := NewLogger(&out, &errOut)
+	l.Streams().DisableStyle()
+
+	derived := l.With("k", "v")
+	l.RegisterChannel("audit", level.Default, types.ChannelOptions{})
+	if audit, ok := derived.Channel("audit"); ok {
+		audit.LogLn("first")
+	}
+
+	l.RegisterChannel("audit", level.Default, types.ChannelOptions{Route: types.ErrStreamRoute})
+	if audit, ok := derived.Channel("audit"); ok {
+		audit.LogLn("second")
+	}
+
+	if expected := "first k=v\n"; out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+
+	if expected := "second k=v\n"; errOut.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, errOut.String())
+	}
+}
+
+func TestLogger_Fatal(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := NewLogger(&out, &errOut)
+	l.Streams().DisableStyle()
+
+	var exitCode int
+	l.SetExitFunc(func(code int) { exitCode = code })
+
+	l.Fatal().LogF("unable to start: %s\n", "no config")
+
+	if exitCode != 1 {
+		t.Errorf("\n[EXPECTED]: %d\n[GOT]: %d", 1, exitCode)
+	}
+
+	if expected := "unable to start: no config\n"; errOut.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, errOut.String())
+	}
+}
+
+func TestManager_SetStreamRoute(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := NewLogger(&out, &errOut)
+	l.Streams().DisableStyle()
+	l.SetAcceptedLevel(level.Info)
+
+	l.Info().SetStreamRoute(types.ErrStreamRoute)
+	l.Error().SetStreamRoute(types.BothStreamsRoute)
+
+	l.With("k", "v").Info().LogLn("info")
+	l.Error().LogLn("error")
+	_, _ = l.OutStream().Write([]byte("out stream\n"))
+	_, _ = l.ErrStream().Write([]byte("err stream\n"))
+
+	if expected := "error\nout stream\n"; out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+
+	if expected := "info k=v\nerror\nerr stream\n"; errOut.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, errOut.String())
+	}
+}
+
+func TestManager_SetStreamRoute_bothStreams(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := NewLogger(&out, &errOut)
+	l.Streams().DisableStyle()
+	l.Streams().SetClock(clock.NewFake(time.Time{}))
+	l.Streams().SetPrefix("> ")
+
+	l.Error().SetStreamRoute(types.BothStreamsRoute)
+	l.Error().LogProcess("process").Do(func() {
+		l.Error().LogF("partial")
+		l.Error().LogLn(" line")
+	})
+
+	if expected := "> ┌ process\n> │ partial line\n> └ process (0.00 seconds)\n"; errOut.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, errOut.String())
+	}
+
+	if expected := "> │ partial line\n"; out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+}
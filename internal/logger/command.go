@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/color"
+
+	"github.com/werf/logboek/pkg/level"
+	"github.com/werf/logboek/pkg/types"
+)
+
+// LogCommand runs the command inside a log process. The command output is
+// written to the proxy streams of the Default (stdout) and Error (stderr)
+// channels, so an incomplete line, e.g. a prompt, is written when the proxy
+// stream data flush timeout expires or when the command ends.
+type LogCommand struct {
+	logger  *Logger
+	cmd     *exec.Cmd
+	options *LogCommandOptions
+}
+
+func (c *LogCommand) Options(f func(options types.LogCommandOptionsInterface)) types.LogCommandInterface {
+	f(c.options)
+	return c
+}
+
+// Run starts the command and waits for it to complete. The command is killed
+// when the context is done. The process fails with the exit status or the
+// signal of the command in the footer.
+func (c *LogCommand) Run(ctx context.Context) error {
+	if c.cmd.Stdout != nil {
+		return errors.New("logboek: Stdout already set")
+	}
+
+	if c.cmd.Stderr != nil {
+		return errors.New("logboek: Stderr already set")
+	}
+
+	stdoutManager := c.logger.getLevelManager(level.Default)
+	stderrManager := c.logger.getLevelManager(level.Error)
+
+	c.cmd.Stdout = stdoutManager.taggedStream(c.options.stdout.style, c.options.stdout.tag, c.options.stdout.tagStyle)
+	c.cmd.Stderr = stderrManager.taggedStream(c.options.stderr.style, c.options.stderr.tag, c.options.stderr.tagStyle)
+
+	format, args := processHeaderOrFormatArg(c.title())
+	if c.options.titleFormat != "" {
+		format, args = c.options.titleFormat, c.options.titleArgs
+	}
+
+	return c.logger.Default().LogProcess(format, args...).
+		Options(func(options types.LogProcessOptionsInterface) {
+			options.FailureReasonFunc(commandFailureReason)
+			if c.options.processOptionsFunc != nil {
+				c.options.processOptionsFunc(options)
+			}
+		}).
+		DoError(func() error {
+			err := c.run(ctx)
+
+			stdoutManager.flushLine()
+			stderrManager.flushLine()
+
+			return err
+		})
+}
+
+func (c *LogCommand) run(ctx context.Context) error {
+	if err := c.cmd.Start(); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = c.cmd.Process.Kill()
+		case <-done:
+		}
+	}()
+
+	err := c.cmd.Wait()
+	close(done)
+
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("%w (%w)", ctx.Err(), err)
+	}
+
+	return err
+}
+
+func (c *LogCommand) title() string {
+	args := c.cmd.Args
+	if len(args) == 0 {
+		args = []string{c.cmd.Path}
+	}
+
+	var parts []string
+	for _, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\") {
+			arg = strconv.Quote(arg)
+		}
+
+		parts = append(parts, arg)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func commandFailureReason(err error) string {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ProcessState.String()
+	}
+
+	return ""
+}
+
+type LogCommandOptions struct {
+	titleFormat        string
+	titleArgs          []interface{}
+	stdout             commandOutputOptions
+	stderr             commandOutputOptions
+	processOptionsFunc func(options types.LogProcessOptionsInterface)
+}
+
+// Title sets the process title instead of the command line.
+func (opts *LogCommandOptions) Title(headerOrFormat string, a ...interface{}) {
+	opts.titleFormat = headerOrFormat
+	opts.titleArgs = a
+}
+
+func (opts *LogCommandOptions) StdoutTag(value string, style color.Style) {
+	opts.stdout.tag = value
+	opts.stdout.tagStyle = style
+}
+
+func (opts *LogCommandOptions) StderrTag(value string, style color.Style) {
+	opts.stderr.tag = value
+	opts.stderr.tagStyle = style
+}
+
+func (opts *LogCommandOptions) StdoutStyle(style color.Style) {
+	opts.stdout.style = style
+}
+
+func (opts *LogCommandOptions) StderrStyle(style color.Style) {
+	opts.stderr.style = style
+}
+
+func (opts *LogCommandOptions) ProcessOptions(f func(options types.LogProcessOptionsInterface)) {
+	opts.processOptionsFunc = f
+}
+
+type commandOutputOptions struct {
+	tag      string
+	tagStyle color.Style
+	style    color.Style
+}
@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/werf/logboek/pkg/types"
+)
+
+func TestLogger_LogCommand(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := NewLogger(&out, &errOut)
+	l.Streams().DisableStyle()
+	l.Streams().SetWidth(80)
+
+	cmd := exec.Command("sh", "-c", "echo out; echo err >&2; printf tail; exit 3")
+	err := l.LogCommand(cmd).Run(context.Background())
+	if err == nil {
+		t.Fatal("expected command error")
+	}
+
+	for _, expected := range []string{"┌ sh -c \"echo out; echo err >&2; printf tail; exit 3\"", "│ out", "│ tail", "FAILED: exit status 3"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("\n[EXPECTED]: %q in\n[GOT]: %q", expected, out.String())
+		}
+	}
+
+	if expected := "│ err"; !strings.Contains(errOut.String(), expected) {
+		t.Errorf("\n[EXPECTED]: %q in\n[GOT]: %q", expected, errOut.String())
+	}
+}
+
+func TestLogger_LogCommand_contextCancel(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := l.LogCommand(exec.Command("sleep", "10")).Run(ctx)
+	if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Fatalf("expected context cancellation error, got %v", err)
+	}
+}
+
+type syncBuffer struct {
+	buf   bytes.Buffer
+	mutex sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buf.String()
+}
+
+func TestLogger_LogCommand_incompleteLine(t *testing.T) {
+	out := &syncBuffer{}
+	l := NewLogger(out, out)
+	l.Streams().DisableStyle()
+	l.Streams().SetProxyStreamDataFlushTimeout(10 * time.Millisecond)
+
+	cmd := exec.Command("sh", "-c", "printf 'Continue? '; read answer; echo \"$answer\"; printf 'done'")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- l.LogCommand(cmd).Options(func(options types.LogCommandOptionsInterface) {
+			options.Title("confirm")
+			options.ProcessOptions(func(options types.LogProcessOptionsInterface) {
+				options.WithoutElapsedTime()
+			})
+		}).Run(context.Background())
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.HasSuffix(out.String(), "Continue? ") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if expected := "┌ confirm\n│ Continue? "; out.String() != expected {
+		t.Fatalf("the prompt is not written before the command ends:\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+
+	_, _ = stdin.Write([]byte("y\n"))
+	_ = stdin.Close()
+
+	if err := <-errCh; err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "┌ confirm\n│ Continue? y\n│ done\n└ confirm\n"; out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+}
+
+func TestLogger_LogCommand_tags(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := NewLogger(&out, &errOut)
+	l.Streams().DisableStyle()
+
+	cmd := exec.Command("sh", "-c", "echo out; echo err >&2")
+	err := l.LogCommand(cmd).Options(func(options types.LogCommandOptionsInterface) {
+		options.StdoutTag("stdout", nil)
+		options.StderrTag("stderr", nil)
+	}).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "│ stdout  out\n"; !strings.Contains(out.String(), expected) {
+		t.Errorf("\n[EXPECTED]: %q in\n[GOT]: %q", expected, out.String())
+	}
+
+	if expected := "│ stderr  err\n"; errOut.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, errOut.String())
+	}
+
+	l.LogLn("untagged")
+	if expected := "untagged\n"; !strings.HasSuffix(out.String(), expected) {
+		t.Errorf("\n[EXPECTED]: %q suffix\n[GOT]: %q", expected, out.String())
+	}
+}
+
+func TestLogger_LogCommand_outputAlreadySet(t *testing.T) {
+	l := NewLogger(&bytes.Buffer{}, &bytes.Buffer{})
+
+	cmd := exec.Command("true")
+	cmd.Stdout = &bytes.Buffer{}
+	if err := l.LogCommand(cmd).Run(context.Background()); err == nil || err.Error() != "logboek: Stdout already set" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cmd = exec.Command("true")
+	cmd.Stderr = &bytes.Buffer{}
+	if err := l.LogCommand(cmd).Run(context.Background()); err == nil || err.Error() != "logboek: Stderr already set" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package logger
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/werf/logboek/pkg/level"
+)
+
+const componentSeparator = "."
+
+type componentLevel struct {
+	pattern string
+	level   level.Level
+	// exact is set for the level of the component set by SetAcceptedLevel: the
+	// pattern is the component name, which is matched literally.
+	exact bool
+}
+
+// parseComponentLevels parses the comma-separated list of pattern=level pairs,
+// e.g. "registry=debug,git.*=info,*=default".
+func parseComponentLevels(spec string) ([]componentLevel, error) {
+	var result []componentLevel
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		pattern, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected pattern=level, got %q", part)
+		}
+
+		pattern = strings.TrimSpace(pattern)
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return nil, fmt.Errorf("bad component pattern %q", pattern)
+		}
+
+		lvl, err := level.ParseLevel(strings.TrimSpace(value))
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, componentLevel{pattern: pattern, level: lvl})
+	}
+
+	return result, nil
+}
+
+// matchComponentLevel returns the level of the most specific pattern matching
+// the component or one of its parents. The specificity is the number of
+// characters in the pattern that are not wildcards; the last of the equally
+// specific patterns wins.
+func matchComponentLevel(componentLevels []componentLevel, component string) (level.Level, bool) {
+	var result level.Level
+	bestSpecificity := -1
+	for _, cl := range componentLevels {
+		if !matchComponent(cl, component) {
+			continue
+		}
+
+		specificity := len(cl.pattern)
+		if !cl.exact {
+			specificity -= strings.Count(cl.pattern, "*") + strings.Count(cl.pattern, "?")
+		}
+
+		if specificity >= bestSpecificity {
+			bestSpecificity = specificity
+			result = cl.level
+		}
+	}
+
+	return result, bestSpecificity != -1
+}
+
+// setComponentLevel returns the component levels with the level of the
+// component replaced. The passed slice is not changed.
+func setComponentLevel(componentLevels []componentLevel, component string, lvl level.Level) []componentLevel {
+	result := make([]componentLevel, 0, len(componentLevels)+1)
+	for _, cl := range componentLevels {
+		if !cl.exact || cl.pattern != component {
+			result = append(result, cl)
+		}
+	}
+
+	return append(result, componentLevel{pattern: component, level: lvl, exact: true})
+}
+
+func matchComponent(cl componentLevel, component string) bool {
+	if cl.exact {
+		return component == cl.pattern || strings.HasPrefix(component, cl.pattern+componentSeparator)
+	}
+
+	pattern := cl.pattern
+	for {
+		if ok, _ := path.Match(pattern, component); ok {
+			return true
+		}
+
+		i := strings.LastIndex(component, componentSeparator)
+		if i == -1 {
+			return false
+		}
+
+		component = component[:i]
+	}
+}
@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/werf/logboek/pkg/level"
+)
+
+func TestLogger_Component(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+
+	if err := l.SetComponentLevels("registry=debug, git.*=info, *=warn"); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := l.Component("registry")
+	registry.Debug().LogLn("registry debug")
+	registry.Component("auth").Debug().LogLn("registry.auth debug")
+	l.Component("git").Info().LogLn("hidden")
+	l.Component("git").Component("fetch").Info().LogLn("git.fetch info")
+	l.Component("build").Default().LogLn("hidden")
+	l.Component("build").Warn().LogLn("build warn")
+	l.Debug().LogLn("hidden")
+	l.LogLn("default")
+
+	if expected := "registry debug\nregistry.auth debug\ngit.fetch info\nbuild warn\ndefault\n"; out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+
+	if name := registry.Component("auth").With("k", "v").ComponentName(); name != "registry.auth" {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", "registry.auth", name)
+	}
+
+	if !l.NewSubLogger(&out, &out).Component("registry").IsAcceptedLevel(level.Debug) {
+		t.Errorf("expected the component levels to be copied to the sub-logger")
+	}
+}
+
+func TestLogger_Component_SetAcceptedLevel(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+
+	if err := l.SetComponentLevels("registry.*=trace"); err != nil {
+		t.Fatal(err)
+	}
+
+	subLogger := l.NewSubLogger(&out, &out)
+
+	registry := l.Component("registry")
+	registry.SetAcceptedLevel(level.Debug)
+	registry.Debug().LogLn("registry debug")
+	registry.Component("cache").Debug().LogLn("registry.cache debug")
+	registry.Component("auth").Trace().LogLn("registry.auth trace")
+	l.Component("registryx").Debug().LogLn("hidden")
+	l.Component("git").Debug().LogLn("hidden")
+	l.Debug().LogLn("hidden")
+
+	if expected := "registry debug\nregistry.cache debug\nregistry.auth trace\n"; out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+
+	if l.AcceptedLevel() != level.Default {
+		t.Errorf("\n[EXPECTED]: %v\n[GOT]: %v", level.Default, l.AcceptedLevel())
+	}
+
+	if subLogger.Component("registry").IsAcceptedLevel(level.Debug) {
+		t.Errorf("expected the component levels of the sub-logger to be kept")
+	}
+}
+
+func TestMatchComponentLevel(t *testing.T) {
+	componentLevels, err := parseComponentLevels("*=warn,registry=debug,registry.*=trace,registry.cache=info")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		component string
+		expected  level.Level
+	}{
+		{"git", level.Warn},
+		{"registry", level.Debug},
+		{"registry.auth", level.Trace},
+		{"registry.auth.token", level.Trace},
+		{"registry.cache", level.Info},
+		{"registry.cache.gc", level.Info},
+		{"registryx", level.Warn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.component, func(t *testing.T) {
+			got, _ := matchComponentLevel(componentLevels, tt.component)
+			if got != tt.expected {
+				t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseComponentLevels_errors(t *testing.T) {
+	for _, spec := range []string{"registry", "registry=loud", "[=debug", "=debug"} {
+		if _, err := parseComponentLevels(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package logger
+
+import "github.com/werf/logboek/internal/stream"
+
+// appendFields returns new fields, so the fields of derived loggers and
+// managers are not shared.
+func appendFields(fields, keysAndValues []interface{}) []interface{} {
+	result := make([]interface{}, 0, len(fields)+len(keysAndValues))
+	result = append(result, fields...)
+	result = append(result, keysAndValues...)
+	if len(keysAndValues)%2 != 0 {
+		result = append(result, stream.MissingFieldValue)
+	}
+
+	return result
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/werf/logboek/pkg/clock"
+)
+
+func TestLogger_With(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+	l.Streams().SetWidth(80)
+	l.Streams().SetClock(clock.NewFake(time.Time{}))
+
+	imageLogger := l.With("image", "alpine:3", "stage")
+	imageLogger.LogProcess("Building").Do(func() {
+		imageLogger.LogLn("pulled")
+		imageLogger.Info().With("digest", "sha256:abc").LogF("%s\n", "done")
+		l.LogLn("no fields")
+	})
+	_ = imageLogger.With("attempt", 2).LogProcess("Pushing").DoError(func() error {
+		return errors.New("denied")
+	})
+
+	expected := `┌ Building
+│ pulled image=alpine:3 stage=(MISSING)
+│ no fields
+└ Building (0.00 seconds) image=alpine:3 stage=(MISSING)
+
+┌ Pushing
+└ Pushing (0.00 seconds) FAILED image=alpine:3 stage=(MISSING) attempt=2
+`
+	if out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+
+	l.SetAcceptedLevel(l.Info().Level())
+	out.Reset()
+	imageLogger.Info().LogLn("accepted")
+	if expected := "\naccepted image=alpine:3 stage=(MISSING)\n"; out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+}
+
+func TestLogger_With_inlineProcessAndBlock(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+	l.Streams().SetWidth(80)
+	l.Streams().SetClock(clock.NewFake(time.Time{}))
+
+	imageLogger := l.With("image", "alpine:3")
+	imageLogger.LogBlock("Manifest").Do(func() {
+		imageLogger.LogLn("layers: 3")
+	})
+	imageLogger.LogProcessInline("Cleaning").Do(func() {})
+	_ = imageLogger.LogProcessInline("Pruning").DoError(func() error {
+		return errors.New("busy")
+	})
+
+	expected := `┌ Manifest image=alpine:3
+│ layers: 3 image=alpine:3
+└ Manifest image=alpine:3
+
+Cleaning ... (0.00 seconds) image=alpine:3
+Pruning ... (0.00 seconds) FAILED image=alpine:3
+`
+	if out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+}
@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/werf/logboek/pkg/types"
+)
+
+func TestLogger_Flush(t *testing.T) {
+	var out bytes.Buffer
+	var l types.LoggerInterface = NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+	l.Streams().EnableLineWrapping()
+	l.Streams().EnableProxyStreamDataFormatting()
+
+	for _, f := range []interface{}{l, l.Default(), l.Error()} {
+		if _, ok := f.(types.Flusher); !ok {
+			t.Fatalf("%T does not implement types.Flusher", f)
+		}
+	}
+
+	_, _ = io.WriteString(l.OutStream(), "incomplete")
+	if out.String() != "" {
+		t.Fatalf("unexpected output before flush: %q", out.String())
+	}
+
+	l.(types.Flusher).Flush()
+	if expected := "incomplete"; out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/werf/logboek/pkg/level"
+	"github.com/werf/logboek/pkg/types"
+)
+
+func TestLogger_EnableLevelLabels(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+	l.SetAcceptedLevel(level.Debug)
+
+	l.EnableLevelLabels()
+	l.Info().SetLabel("ℹ")
+	l.RegisterChannel("audit", level.Info, types.ChannelOptions{Label: "AUDIT"})
+
+	l.Error().LogF("error\n")
+	l.Warn().LogLn("warn")
+	l.LogLn("default")
+	l.Info().LogLn("info")
+	l.With("k", "v").Debug().LogLn("debug")
+	audit, _ := l.Channel("audit")
+	audit.LogLn("audit")
+	l.Warn().LogLn()
+
+	l.DisableLevelLabels()
+	l.Error().LogLn("error")
+
+	expected := "ERROR error\nWARN warn\ndefault\nℹ info\nDEBUG debug k=v\nAUDIT audit\n\nerror\n"
+	if out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+}
+
+func TestLogger_EnableLevelLabels_multiLine(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+
+	l.EnableLevelLabels()
+	l.With("k", "v").Error().LogF("first\n\nsecond\n")
+	l.Warn().LogF("partial")
+	l.Warn().LogLn(" line")
+
+	expected := "ERROR first\n\nERROR second k=v\nWARN partial line\n"
+	if out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+}
+
+func TestLogger_EnableLevelLabels_derived(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+
+	derived := l.With("k", "v")
+	component := l.Component("registry")
+
+	l.EnableLevelLabels()
+	derived.Warn().LogLn("derived")
+	component.Warn().LogLn("component")
+
+	l.DisableLevelLabels()
+	derived.Warn().LogLn("derived")
+
+	expected := "WARN derived k=v\nWARN component\nderived k=v\n"
+	if out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+}
+
+func TestLogger_EnableCallerLocation(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+	l.Streams().EnableCallerLocation()
+	l.SetAcceptedLevel(level.Trace)
+
+	l.Info().LogLn("info")
+	_, _, line, _ := runtime.Caller(0)
+	l.Debug().LogLn("debug")
+	l.Trace().LogF("trace %s\n", "message")
+
+	expected := fmt.Sprintf("info\ndebug caller=label_test.go:%d\ntrace message caller=label_test.go:%d\n", line+1, line+2)
+	if out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+}
@@ -3,6 +3,10 @@ package logger
 import (
 	"fmt"
 	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
 
 	"github.com/gookit/color"
 
@@ -13,20 +17,79 @@ import (
 
 type Logger struct {
 	*Manager
+	*loggerState
 
+	levelManager   map[level.Level]*Manager
+	channelManager map[string]*Manager
+	fields         []interface{}
+	component      string
+}
+
+// loggerState is shared by the logger and the loggers derived with With.
+type loggerState struct {
 	acceptedLevel             level.Level
-	levelManager              map[level.Level]*Manager
 	outStream                 *stream.Stream
 	errStream                 *stream.Stream
+	outCopyStream             *stream.Stream
+	errCopyStream             *stream.Stream
 	commonStreamStateAndModes *stream.StateAndModes
+	exitFunc                  func(code int)
+
+	channels      map[string]channelRegistration
+	channelsMutex sync.Mutex
+
+	componentLevels      []componentLevel
+	componentLevelsMutex sync.RWMutex
+
+	// parent is the state of the logger the sub-logger is created by, whose
+	// accepted level overrides are inherited.
+	parent                      *loggerState
+	acceptedLevelOverrides      []acceptedLevelOverride
+	lastAcceptedLevelOverrideId uint64
+	acceptedLevelOverridesMutex sync.Mutex
+}
+
+// acceptedLevelOverride is the level set by DoWithAcceptedLevel, identified
+// so that the calls running concurrently remove their own overrides.
+type acceptedLevelOverride struct {
+	id    uint64
+	level level.Level
+}
+
+func (s *loggerState) acceptedLevelOverride() (level.Level, bool) {
+	s.acceptedLevelOverridesMutex.Lock()
+	if n := len(s.acceptedLevelOverrides); n != 0 {
+		defer s.acceptedLevelOverridesMutex.Unlock()
+		return s.acceptedLevelOverrides[n-1].level, true
+	}
+	s.acceptedLevelOverridesMutex.Unlock()
+
+	if s.parent != nil {
+		return s.parent.acceptedLevelOverride()
+	}
+
+	return 0, false
+}
+
+type channelRegistration struct {
+	level   level.Level
+	options types.ChannelOptions
+	route   *types.StreamRoute
+	label   *managerLabel
 }
 
 func NewLogger(outStream, errStream io.Writer) *Logger {
-	l := &Logger{}
+	l := &Logger{loggerState: &loggerState{
+		exitFunc: os.Exit,
+		channels: map[string]channelRegistration{},
+	}}
 
 	l.commonStreamStateAndModes = stream.NewStreamState()
 	l.outStream = stream.NewStream(outStream, l.commonStreamStateAndModes)
 	l.errStream = stream.NewStream(errStream, l.commonStreamStateAndModes)
+	l.outCopyStream = stream.NewCopyStream(outStream, l.commonStreamStateAndModes)
+	l.errCopyStream = stream.NewCopyStream(errStream, l.commonStreamStateAndModes)
+	l.commonStreamStateAndModes.DetectColorLevel(outStream, errStream)
 	l.initLevelManager()
 
 	return l
@@ -36,8 +99,11 @@ func (l *Logger) initLevelManager() {
 	l.levelManager = make(map[level.Level]*Manager, len(level.List))
 	for _, lvl := range level.List {
 		l.levelManager[lvl] = NewManager(l, lvl)
+		l.levelManager[lvl].fields = l.fields
 	}
 
+	l.channelManager = map[string]*Manager{}
+
 	l.Manager = l.levelManager[level.Default]
 }
 
@@ -45,6 +111,8 @@ func (l *Logger) setCommonStreamState(state *stream.StateAndModes) {
 	l.commonStreamStateAndModes = state
 	l.outStream.StateAndModes = state
 	l.errStream.StateAndModes = state
+	l.outCopyStream.StateAndModes = state
+	l.errCopyStream.StateAndModes = state
 }
 
 func (l *Logger) getLevelManager(lvl level.Level) *Manager {
@@ -52,13 +120,35 @@ func (l *Logger) getLevelManager(lvl level.Level) *Manager {
 }
 
 func (l *Logger) GetLevelStream(lvl level.Level) *stream.Stream {
-	if lvl == level.Error || lvl == level.Warn {
-		return l.errStream
+	return l.getRouteStreams(lvl, types.DefaultStreamRoute)[0]
+}
+
+// getRouteStreams returns the streams of the route. The first one is used for
+// log processes, blocks and proxy stream data.
+func (l *Logger) getRouteStreams(lvl level.Level, route types.StreamRoute) []*stream.Stream {
+	switch route {
+	case types.OutStreamRoute:
+		return []*stream.Stream{l.outStream}
+	case types.ErrStreamRoute:
+		return []*stream.Stream{l.errStream}
+	case types.BothStreamsRoute:
+		if lvl <= level.Warn {
+			return []*stream.Stream{l.errStream, l.outCopyStream}
+		}
+		return []*stream.Stream{l.outStream, l.errCopyStream}
+	}
+
+	if lvl <= level.Warn {
+		return []*stream.Stream{l.errStream}
 	} else {
-		return l.outStream
+		return []*stream.Stream{l.outStream}
 	}
 }
 
+func (l *Logger) Fatal() types.ManagerInterface {
+	return l.getLevelManager(level.Fatal)
+}
+
 func (l *Logger) Error() types.ManagerInterface {
 	return l.getLevelManager(level.Error)
 }
@@ -79,12 +169,186 @@ func (l *Logger) Debug() types.ManagerInterface {
 	return l.getLevelManager(level.Debug)
 }
 
+func (l *Logger) Trace() types.ManagerInterface {
+	return l.getLevelManager(level.Trace)
+}
+
+// EnableLevelLabels sets the labels of the predefined level channels, except
+// Default, to the upper-case level names. The labels are shared with the
+// loggers derived with With and Component.
+func (l *Logger) EnableLevelLabels() {
+	for lvl, manager := range l.levelManager {
+		if lvl != level.Default {
+			manager.label.value = strings.ToUpper(lvl.String())
+		}
+	}
+}
+
+// DisableLevelLabels removes the labels of the predefined level channels.
+func (l *Logger) DisableLevelLabels() {
+	for _, manager := range l.levelManager {
+		manager.label.value = ""
+	}
+}
+
+// SetExitFunc sets the function called after a message of the Fatal channel
+// (os.Exit by default).
+func (l *Logger) SetExitFunc(f func(code int)) {
+	l.exitFunc = f
+}
+
+// RegisterChannel registers the named channel with its own level, stream
+// route and style. The channel is available in the sub-loggers and the loggers
+// derived with With.
+func (l *Logger) RegisterChannel(name string, lvl level.Level, options types.ChannelOptions) types.ManagerInterface {
+	l.channelsMutex.Lock()
+	route := options.Route
+	label := managerLabel{value: options.Label, style: options.LabelStyle}
+	l.channels[name] = channelRegistration{level: lvl, options: options, route: &route, label: &label}
+	l.channelsMutex.Unlock()
+
+	m, _ := l.Channel(name)
+	return m
+}
+
+// Channel returns the registered channel and whether it is registered.
+func (l *Logger) Channel(name string) (types.ManagerInterface, bool) {
+	l.channelsMutex.Lock()
+	defer l.channelsMutex.Unlock()
+
+	registration, ok := l.channels[name]
+	if !ok {
+		return nil, false
+	}
+
+	// The manager is cached by the logger, so it is dropped when the channel
+	// is registered again, e.g. through another logger derived with With.
+	if m, ok := l.channelManager[name]; ok && m.route == registration.route {
+		return m, true
+	}
+
+	m := NewManager(l, registration.level)
+	m.style = registration.options.Style
+	m.label = registration.label
+	m.route = registration.route
+	m.fields = l.fields
+	l.channelManager[name] = m
+
+	return m, true
+}
+
+// AcceptedLevel returns the accepted level of the logger component, or the
+// level set by DoWithAcceptedLevel while its function is running.
 func (l *Logger) AcceptedLevel() level.Level {
+	if lvl, ok := l.acceptedLevelOverride(); ok {
+		return lvl
+	}
+
+	if l.component == "" {
+		return l.acceptedLevel
+	}
+
+	l.componentLevelsMutex.RLock()
+	defer l.componentLevelsMutex.RUnlock()
+
+	if lvl, ok := matchComponentLevel(l.componentLevels, l.component); ok {
+		return lvl
+	}
+
 	return l.acceptedLevel
 }
 
+// SetAcceptedLevel sets the accepted level of the logger and the components
+// not matched by the component levels. For a component logger, the level of
+// the component and its nested components is set, as SetComponentLevels does
+// for the component name.
 func (l *Logger) SetAcceptedLevel(lvl level.Level) {
-	l.acceptedLevel = lvl
+	if l.component == "" {
+		l.acceptedLevel = lvl
+		return
+	}
+
+	l.componentLevelsMutex.Lock()
+	l.componentLevels = setComponentLevel(l.componentLevels, l.component, lvl)
+	l.componentLevelsMutex.Unlock()
+}
+
+// DoWithAcceptedLevel runs the function with the accepted level of the logger,
+// its components, derived loggers and sub-loggers overridden.
+func (l *Logger) DoWithAcceptedLevel(lvl level.Level, f func()) {
+	_ = l.DoErrorWithAcceptedLevel(lvl, func() error {
+		f()
+		return nil
+	})
+}
+
+// DoErrorWithAcceptedLevel is DoWithAcceptedLevel for a function returning an
+// error. The override is logger-wide: while the function is running, the level
+// applies to the messages written from any goroutine, and the override started
+// last wins.
+func (l *Logger) DoErrorWithAcceptedLevel(lvl level.Level, f func() error) error {
+	id := l.pushAcceptedLevelOverride(lvl)
+	defer l.removeAcceptedLevelOverride(id)
+
+	return f()
+}
+
+func (s *loggerState) pushAcceptedLevelOverride(lvl level.Level) uint64 {
+	s.acceptedLevelOverridesMutex.Lock()
+	defer s.acceptedLevelOverridesMutex.Unlock()
+
+	s.lastAcceptedLevelOverrideId++
+	s.acceptedLevelOverrides = append(s.acceptedLevelOverrides, acceptedLevelOverride{id: s.lastAcceptedLevelOverrideId, level: lvl})
+
+	return s.lastAcceptedLevelOverrideId
+}
+
+func (s *loggerState) removeAcceptedLevelOverride(id uint64) {
+	s.acceptedLevelOverridesMutex.Lock()
+	defer s.acceptedLevelOverridesMutex.Unlock()
+
+	overrides := make([]acceptedLevelOverride, 0, len(s.acceptedLevelOverrides))
+	for _, override := range s.acceptedLevelOverrides {
+		if override.id != id {
+			overrides = append(overrides, override)
+		}
+	}
+
+	s.acceptedLevelOverrides = overrides
+}
+
+// SetComponentLevels sets the accepted levels of the components by the
+// comma-separated list of pattern=level pairs, e.g.
+// "registry=debug,git.*=info,*=default". A pattern also matches the nested
+// components, and the most specific pattern wins. The levels set by
+// SetAcceptedLevel of the component loggers are replaced as well.
+func (l *Logger) SetComponentLevels(spec string) error {
+	componentLevels, err := parseComponentLevels(spec)
+	if err != nil {
+		return err
+	}
+
+	l.componentLevelsMutex.Lock()
+	l.componentLevels = componentLevels
+	l.componentLevelsMutex.Unlock()
+
+	return nil
+}
+
+// Component returns a logger of the named component writing to the same
+// streams. The name of a nested component is joined with the parent one by a
+// dot, e.g. "registry.auth".
+func (l *Logger) Component(name string) types.LoggerInterface {
+	component := name
+	if l.component != "" {
+		component = l.component + componentSeparator + name
+	}
+
+	return l.derive(l.fields, component)
+}
+
+func (l *Logger) ComponentName() string {
+	return l.component
 }
 
 func (l *Logger) IsAcceptedLevel(lvl level.Level) bool {
@@ -111,22 +375,80 @@ func (l *Logger) ColorizeF(style color.Style, format string, a ...interface{}) s
 	return l.commonStreamStateAndModes.FormatWithStyle(style, format, a...)
 }
 
+// OutStream writes to the out stream regardless of the Default channel route.
 func (l *Logger) OutStream() io.Writer {
-	return l.Default().Stream()
+	return l.getLevelManager(level.Default).routedStream(types.OutStreamRoute)
 }
 
+// ErrStream writes to the err stream regardless of the Error channel route.
 func (l *Logger) ErrStream() io.Writer {
-	return l.Error().Stream()
+	return l.getLevelManager(level.Error).routedStream(types.ErrStreamRoute)
 }
 
+// Flush writes the incomplete lines of data written to the logger streams.
+func (l *Logger) Flush() {
+	l.outStream.Flush()
+	l.errStream.Flush()
+}
+
+// With returns a logger writing to the same streams, whose messages and
+// processes carry the fields.
+func (l *Logger) With(keysAndValues ...interface{}) types.LoggerInterface {
+	return l.derive(appendFields(l.fields, keysAndValues), l.component)
+}
+
+func (l *Logger) derive(fields []interface{}, component string) *Logger {
+	derived := &Logger{
+		loggerState: l.loggerState,
+		fields:      fields,
+		component:   component,
+	}
+	derived.initLevelManager()
+
+	for lvl, manager := range l.levelManager {
+		derived.levelManager[lvl].style = manager.style
+		derived.levelManager[lvl].label = manager.label
+		derived.levelManager[lvl].route = manager.route
+	}
+
+	return derived
+}
+
+func (l *Logger) LogCommand(cmd *exec.Cmd) types.LogCommandInterface {
+	return &LogCommand{logger: l, cmd: cmd, options: &LogCommandOptions{}}
+}
+
+// NewSubLogger returns a logger writing to the streams with the settings,
+// levels and channels of the logger. The sub-logger gets a copy of the
+// component levels, so their later changes in one of the loggers do not
+// affect the other one.
 func (l *Logger) NewSubLogger(outStream, errStream io.Writer) types.LoggerInterface {
 	subLogger := NewLogger(outStream, errStream)
 	subLogger.setCommonStreamState(l.commonStreamStateAndModes.SubState())
+	subLogger.commonStreamStateAndModes.DetectColorLevel(outStream, errStream)
 	subLogger.SetAcceptedLevel(l.acceptedLevel)
+	subLogger.component = l.component
+	subLogger.parent = l.loggerState
+
+	l.componentLevelsMutex.RLock()
+	subLogger.componentLevels = l.componentLevels
+	l.componentLevelsMutex.RUnlock()
+
+	subLogger.exitFunc = l.exitFunc
 
 	for lvl, manager := range l.levelManager {
 		subLogger.levelManager[lvl].style = manager.style
+		*subLogger.levelManager[lvl].label = *manager.label
+		*subLogger.levelManager[lvl].route = *manager.route
+	}
+
+	l.channelsMutex.Lock()
+	for name, registration := range l.channels {
+		route, label := *registration.route, *registration.label
+		registration.route, registration.label = &route, &label
+		subLogger.channels[name] = registration
 	}
+	l.channelsMutex.Unlock()
 
 	return subLogger
 }
@@ -137,10 +459,16 @@ func (l *Logger) GetStreamsSettingsFrom(l2 types.LoggerInterface) {
 
 func (l *Logger) Reset() {
 	l.outStream.Reset()
+	l.errStream.Reset()
+	l.outCopyStream.ResetState()
+	l.errCopyStream.ResetState()
 }
 
 func (l *Logger) ResetState() {
 	l.outStream.ResetState()
+	l.errStream.ResetState()
+	l.outCopyStream.ResetState()
+	l.errCopyStream.ResetState()
 }
 
 func (l *Logger) ResetModes() {
@@ -16,12 +16,24 @@ type Manager struct {
 	level  level.Level
 	logger *Logger
 	style  color.Style
+	label  *managerLabel
+	route  *types.StreamRoute
+	fields []interface{}
+}
+
+// managerLabel is shared by the manager and the managers derived with With,
+// like the route.
+type managerLabel struct {
+	value string
+	style color.Style
 }
 
 func NewManager(logger *Logger, lvl level.Level) *Manager {
 	return &Manager{
 		logger: logger,
 		level:  lvl,
+		label:  &managerLabel{},
+		route:  new(types.StreamRoute),
 	}
 }
 
@@ -33,8 +45,69 @@ func (m *Manager) Style() color.Style {
 	return m.style
 }
 
+// SetLabel sets the label written before the lines of the messages, e.g.
+// "ERROR" or an icon. The label is shared with the managers derived with With.
+func (m *Manager) SetLabel(label string) {
+	m.label.value = label
+}
+
+func (m *Manager) Label() string {
+	return m.label.value
+}
+
+// SetLabelStyle sets the style of the label (the message style by default).
+func (m *Manager) SetLabelStyle(style color.Style) {
+	m.label.style = style
+}
+
+func (m *Manager) LabelStyle() color.Style {
+	return m.label.style
+}
+
+// SetStreamRoute sets the stream of the channel messages. The route is shared
+// with the managers derived with With.
+func (m *Manager) SetStreamRoute(route types.StreamRoute) {
+	*m.route = route
+}
+
+func (m *Manager) StreamRoute() types.StreamRoute {
+	return *m.route
+}
+
+func (m *Manager) Level() level.Level {
+	return m.level
+}
+
 func (m *Manager) IsAccepted() bool {
-	return m.level <= m.logger.acceptedLevel
+	return m.level <= m.logger.AcceptedLevel()
+}
+
+// With returns a manager whose messages and processes carry the fields.
+func (m *Manager) With(keysAndValues ...interface{}) types.ManagerInterface {
+	derived := *m
+	derived.fields = appendFields(m.fields, keysAndValues)
+	return &derived
+}
+
+// DoErrorWithAcceptedLevel runs the function with the accepted level of the
+// manager logger overridden.
+func (m *Manager) DoErrorWithAcceptedLevel(lvl level.Level, f func() error) error {
+	return m.logger.DoErrorWithAcceptedLevel(lvl, f)
+}
+
+// Flush writes the incomplete line of data written to the stream of the manager.
+func (m *Manager) Flush() {
+	for _, s := range m.getStreams() {
+		s.Flush()
+	}
+}
+
+// flushLine writes the incomplete line of data written to the stream of the
+// manager and ends it.
+func (m *Manager) flushLine() {
+	for _, s := range m.getStreams() {
+		s.FlushLine()
+	}
 }
 
 func (m *Manager) Log(a ...interface{}) {
@@ -99,6 +172,7 @@ func (m *Manager) LogBlock(headerOrFormat string, a ...interface{}) types.LogBlo
 	logBlock := m.getStream().NewLogBlock(m, format, args...)
 	logBlock.Options(func(options types.LogBlockOptionsInterface) {
 		options.Style(m.style)
+		options.Fields(m.fields...)
 	})
 	return logBlock
 }
@@ -109,6 +183,7 @@ func (m *Manager) LogProcessInline(headerOrFormat string, a ...interface{}) type
 	logProcessInline := m.getStream().NewLogProcessInline(m, format, args...)
 	logProcessInline.Options(func(options types.LogProcessInlineOptionsInterface) {
 		options.Style(m.style)
+		options.Fields(m.fields...)
 	})
 	return logProcessInline
 }
@@ -119,6 +194,7 @@ func (m *Manager) LogProcess(headerOrFormat string, a ...interface{}) types.LogP
 	logProcess := m.getStream().NewLogProcess(m, format, args...)
 	logProcess.Options(func(options types.LogProcessOptionsInterface) {
 		options.Style(m.style)
+		options.Fields(m.fields...)
 	})
 	return logProcess
 }
@@ -140,23 +216,87 @@ func (m *Manager) logLnCustom(style color.Style, a ...interface{}) {
 }
 
 func (m *Manager) logFCustom(style color.Style, format string, a ...interface{}) {
+	if m.level == level.Fatal {
+		defer m.exit()
+	}
+
 	if !m.IsAccepted() {
 		return
 	}
 
-	m.getStream().FormatAndLogF(style, false, format, a...)
+	fields := m.fields
+	if m.level >= level.Debug && m.logger.Streams().IsCallerLocationEnabled() {
+		if location := callerLocation(); location != "" {
+			fields = appendFields(fields, []interface{}{"caller", location})
+		}
+	}
+
+	for _, s := range m.getStreams() {
+		switch {
+		case m.label.value != "":
+			s.FormatAndLogFWithLevelAndLabel(m.level, style, m.label.value, m.label.style, fields, format, a...)
+		case len(fields) != 0:
+			s.FormatAndLogFWithLevelAndFields(m.level, style, fields, format, a...)
+		default:
+			s.FormatAndLogFWithLevel(m.level, style, false, format, a...)
+		}
+	}
+}
+
+// exit flushes the streams and runs the exit function after a fatal message.
+func (m *Manager) exit() {
+	m.logger.Flush()
+	m.logger.exitFunc(1)
 }
 
 func (m *Manager) getStream() *stream.Stream {
-	return m.logger.GetLevelStream(m.level)
+	return m.getStreams()[0]
+}
+
+func (m *Manager) getStreams() []*stream.Stream {
+	return m.logger.getRouteStreams(m.level, *m.route)
 }
 
 func (m *Manager) Stream() io.Writer {
 	return proxyStream{Manager: m}
 }
 
+// routedStream returns a proxy stream writing to the streams of the route
+// instead of the manager route.
+func (m *Manager) routedStream(route types.StreamRoute) io.Writer {
+	return proxyStream{Manager: m, customRoute: &route}
+}
+
+// styledStream returns a proxy stream formatting data with the given style
+// instead of the manager style, if the style is set.
+func (m *Manager) styledStream(style color.Style) io.Writer {
+	return proxyStream{Manager: m, customStyle: style}
+}
+
+// taggedStream returns a proxy stream formatting data with the given style
+// instead of the manager style, if the style is set, and with the tag added
+// after the current tags.
+func (m *Manager) taggedStream(style color.Style, tagValue string, tagStyle color.Style) io.Writer {
+	return proxyStream{Manager: m, customStyle: style, tagValue: tagValue, tagStyle: tagStyle}
+}
+
 type proxyStream struct {
 	*Manager
+	customStyle color.Style
+	customRoute *types.StreamRoute
+	tagValue    string
+	tagStyle    color.Style
+}
+
+// stream returns the first stream of the route: the incomplete lines of
+// proxied data are cached in the state shared by the streams, so the data
+// cannot be duplicated.
+func (s proxyStream) stream() *stream.Stream {
+	if s.customRoute != nil {
+		return s.logger.getRouteStreams(s.Manager.level, *s.customRoute)[0]
+	}
+
+	return s.Manager.getStream()
 }
 
 func (s proxyStream) Write(data []byte) (int, error) {
@@ -165,9 +305,14 @@ func (s proxyStream) Write(data []byte) (int, error) {
 	}
 
 	if !s.logger.Streams().IsProxyStreamDataFormattingEnabled() {
-		return s.Manager.getStream().Write(data)
+		return s.stream().Write(data)
+	}
+
+	style := s.Manager.style
+	if s.customStyle != nil {
+		style = s.customStyle
 	}
 
-	s.getStream().FormatAndLogF(s.Manager.style, true, "%s", string(data))
+	s.stream().FormatAndLogTaggedProxyData(s.Manager.level, style, s.tagValue, s.tagStyle, string(data))
 	return len(data), nil
 }
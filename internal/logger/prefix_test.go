@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/werf/logboek/pkg/clock"
+	"github.com/werf/logboek/pkg/types"
+)
+
+func TestStreams_prefixFunc(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+	l.Streams().SetWidth(40)
+	l.SetAcceptedLevel(1000)
+	l.Streams().SetClock(clock.NewFake(time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)))
+
+	l.Streams().SetPrefixFunc(func(ctx types.PrefixContext) string {
+		return fmt.Sprintf("%d:%d:%d", ctx.LineNumber, ctx.Level, ctx.ProcessDepth)
+	})
+
+	l.LogProcess("process").Do(func() {
+		l.Info().LogLn("info")
+		l.Warn().LogLn("warn")
+	})
+
+	expected := "1:0:0 ┌ process\n2:10:1 │ info\n3:-10:1 │ warn\n4:0:0 └ process (0.00 seconds)\n"
+	if out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+}
+
+func TestStreams_appendPrefixFunc(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+	l.Streams().SetWidth(30)
+	l.Streams().SetClock(clock.NewFake(time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)))
+
+	l.Streams().SetPrefixTimeFormat("15:04:05")
+	l.Streams().EnablePrefixTime()
+	l.Streams().AppendPrefixFunc(func(types.PrefixContext) string { return "host" })
+	l.Streams().AppendPrefixFunc(func(types.PrefixContext) string { return "" })
+	l.Streams().AppendPrefixFunc(func(types.PrefixContext) string { return "\x1b[32mworker-3\x1b[0m" })
+
+	if width := l.Streams().ServiceWidth(); width != len("10:00:00 host worker-3 ") {
+		t.Errorf("\n[EXPECTED]: %d\n[GOT]: %d", len("10:00:00 host worker-3 "), width)
+	}
+
+	l.LogLn("message")
+
+	if expected := "10:00:00 host \x1b[32mworker-3\x1b[0m message\n"; out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+}
+
+func TestStreams_prefixSettersReplacePrefix(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+	l.Streams().SetClock(clock.NewFake(time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)))
+	l.Streams().SetPrefixTimeFormat("15:04:05")
+
+	segment := func(types.PrefixContext) string { return "segment" }
+
+	for _, set := range []func(){
+		func() { l.Streams().SetPrefix("static ") },
+		l.Streams().EnablePrefixTime,
+		func() { l.Streams().SetPrefixFunc(func(types.PrefixContext) string { return "func" }) },
+	} {
+		l.Streams().AppendPrefixFunc(segment)
+		set()
+		l.LogLn("message")
+	}
+
+	l.Streams().AppendPrefixFunc(segment)
+	l.Streams().DisablePrefix()
+	l.LogLn("message")
+
+	expected := "static message\n10:00:00 message\nfunc message\nmessage\n"
+	if out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+}
+
+func TestStreams_prefixFuncCalledOncePerLine(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+	l.Streams().SetWidth(12)
+	l.Streams().EnableLineWrapping()
+
+	var calls int
+	l.Streams().SetPrefixFunc(func(types.PrefixContext) string {
+		calls++
+		return fmt.Sprintf("[%d]", calls)
+	})
+
+	l.LogLn("first")
+	l.LogLn("second line")
+	l.LogLn("third")
+
+	expected := "[1] first\n[2] second ↵\n[3] line\n[4] third\n"
+	if out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+}
+
+func TestStreams_prefixFuncLineNumberAfterCarriageReturn(t *testing.T) {
+	var out bytes.Buffer
+	l := NewLogger(&out, &out)
+	l.Streams().DisableStyle()
+	l.Streams().SetWidth(40)
+
+	l.Streams().SetPrefixFunc(func(ctx types.PrefixContext) string {
+		return fmt.Sprintf("%d", ctx.LineNumber)
+	})
+
+	l.LogF("10%%\r20%%\ndone\n")
+
+	if expected := "1 10%\r1 20%\n2 done\n"; out.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, out.String())
+	}
+}
@@ -0,0 +1,89 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/werf/logboek/pkg/level"
+	"github.com/werf/logboek/pkg/types"
+)
+
+const (
+	LevelEnv      = "LOGBOEK_LEVEL"
+	ColorEnv      = "LOGBOEK_COLOR"
+	WidthEnv      = "LOGBOEK_WIDTH"
+	FormatEnv     = "LOGBOEK_FORMAT"
+	PrefixTimeEnv = "LOGBOEK_PREFIX_TIME"
+	BordersEnv    = "LOGBOEK_BORDERS"
+	ComponentsEnv = "LOGBOEK_COMPONENTS"
+)
+
+// ApplyEnv applies the set environment variables to the logger. The valid
+// variables are applied even if others are invalid.
+func ApplyEnv(logger types.LoggerInterface, lookupEnv func(key string) (string, bool)) error {
+	var errs []error
+	apply := func(key string, f func(value string) error) {
+		value, ok := lookupEnv(key)
+		if !ok || value == "" {
+			return
+		}
+
+		if err := f(value); err != nil {
+			errs = append(errs, fmt.Errorf("bad %s: %w", key, err))
+		}
+	}
+
+	streams := logger.Streams()
+
+	apply(LevelEnv, func(value string) error {
+		lvl, err := level.ParseLevel(value)
+		if err != nil {
+			return err
+		}
+
+		logger.SetAcceptedLevel(lvl)
+		return nil
+	})
+
+	apply(ComponentsEnv, logger.SetComponentLevels)
+
+	apply(ColorEnv, func(value string) error {
+		return SetColor(streams, value)
+	})
+
+	apply(WidthEnv, func(value string) error {
+		width, err := ParseWidth(value)
+		if err != nil {
+			return err
+		}
+
+		return SetWidth(streams, width)
+	})
+
+	apply(FormatEnv, func(value string) error {
+		return SetFormat(streams, value)
+	})
+
+	apply(PrefixTimeEnv, func(value string) error {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+
+		SetPrefixTime(streams, enabled)
+		return nil
+	})
+
+	apply(BordersEnv, func(value string) error {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+
+		SetLogProcessBorder(streams, enabled)
+		return nil
+	})
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,78 @@
+package settings
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/werf/logboek/internal/logger"
+	"github.com/werf/logboek/pkg/level"
+)
+
+func lookupEnvFunc(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	l := logger.NewLogger(&bytes.Buffer{}, &bytes.Buffer{})
+
+	err := ApplyEnv(l, lookupEnvFunc(map[string]string{
+		LevelEnv:      "debug",
+		ColorEnv:      "off",
+		WidthEnv:      "72",
+		FormatEnv:     "plain",
+		PrefixTimeEnv: "true",
+		BordersEnv:    "",
+		ComponentsEnv: "registry=trace",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	streams := l.Streams()
+	if l.AcceptedLevel() != level.Debug || streams.IsStyleEnabled() || streams.Width() != 72 || !streams.IsPrefixTimeEnabled() {
+		t.Errorf("unexpected settings: level %v, style %v, width %d, time prefix %v", l.AcceptedLevel(), streams.IsStyleEnabled(), streams.Width(), streams.IsPrefixTimeEnabled())
+	}
+
+	if lvl := l.Component("registry").AcceptedLevel(); lvl != level.Trace {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", level.Trace, lvl)
+	}
+
+	if streams.IsLogProcessBorderEnabled() || streams.IsLineWrappingEnabled() {
+		t.Errorf("expected plain format")
+	}
+
+	if err := ApplyEnv(l, lookupEnvFunc(map[string]string{BordersEnv: "1"})); err != nil {
+		t.Fatal(err)
+	}
+
+	if !streams.IsLogProcessBorderEnabled() {
+		t.Errorf("expected borders to be enabled")
+	}
+}
+
+func TestApplyEnv_errors(t *testing.T) {
+	l := logger.NewLogger(&bytes.Buffer{}, &bytes.Buffer{})
+
+	err := ApplyEnv(l, lookupEnvFunc(map[string]string{
+		LevelEnv: "loud",
+		WidthEnv: "wide",
+		ColorEnv: "off",
+	}))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	for _, expected := range []string{"bad LOGBOEK_LEVEL", "bad LOGBOEK_WIDTH"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("\n[EXPECTED]: %q in\n[GOT]: %q", expected, err.Error())
+		}
+	}
+
+	if l.Streams().IsStyleEnabled() {
+		t.Errorf("expected valid variables to be applied")
+	}
+}
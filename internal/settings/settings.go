@@ -0,0 +1,107 @@
+// Package settings applies the logger settings given as text by the command
+// line flags and the environment variables.
+package settings
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/werf/logboek/pkg/types"
+)
+
+const (
+	ColorAuto = "auto"
+	ColorOn   = "on"
+	ColorOff  = "off"
+)
+
+// SetColor sets the style mode: auto, on or off (true/false, yes/no and 1/0
+// are accepted as well).
+func SetColor(streams types.StreamsInterface, value string) error {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case ColorAuto, "":
+		streams.ResetStyle()
+	case ColorOn, "true", "yes", "always", "1":
+		streams.EnableStyle()
+	case ColorOff, "false", "no", "never", "0":
+		streams.DisableStyle()
+	default:
+		return fmt.Errorf("unknown color mode %q: expected auto, on or off", value)
+	}
+
+	return nil
+}
+
+func SetWidth(streams types.StreamsInterface, value int) error {
+	if value <= 0 {
+		return fmt.Errorf("terminal width must be positive, got %d", value)
+	}
+
+	streams.SetWidth(value)
+	return nil
+}
+
+func ParseWidth(value string) (int, error) {
+	width, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("bad terminal width %q: %w", value, err)
+	}
+
+	return width, nil
+}
+
+// SetPretty switches between the pretty output (process borders, line
+// wrapping and proxy stream data formatting) and the plain one.
+func SetPretty(streams types.StreamsInterface, pretty bool) {
+	if pretty {
+		streams.EnableProxyStreamDataFormatting()
+		streams.EnableLogProcessBorder()
+		streams.EnableLineWrapping()
+	} else {
+		streams.DisablePrettyLog()
+	}
+}
+
+func SetPrefixTime(streams types.StreamsInterface, enabled bool) {
+	if enabled {
+		streams.EnablePrefixTime()
+	} else {
+		streams.DisablePrefixTime()
+	}
+}
+
+func SetGitlabCollapsibleSections(streams types.StreamsInterface, enabled bool) {
+	if enabled {
+		streams.EnableGitlabCollapsibleSections()
+	} else {
+		streams.DisableGitlabCollapsibleSections()
+	}
+}
+
+const (
+	FormatPretty = "pretty"
+	FormatPlain  = "plain"
+)
+
+// SetFormat sets the pretty or plain output.
+func SetFormat(streams types.StreamsInterface, value string) error {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case FormatPretty:
+		SetPretty(streams, true)
+	case FormatPlain:
+		SetPretty(streams, false)
+	default:
+		return fmt.Errorf("unknown format %q: expected pretty or plain", value)
+	}
+
+	return nil
+}
+
+func SetLogProcessBorder(streams types.StreamsInterface, enabled bool) {
+	if enabled {
+		streams.EnableLogProcessBorder()
+	} else {
+		streams.DisableLogProcessBorder()
+	}
+}
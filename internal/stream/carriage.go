@@ -0,0 +1,98 @@
+package stream
+
+import (
+	"strings"
+	"time"
+)
+
+const carriageReturnRedrawInterval = 200 * time.Millisecond
+
+// carriageReturnCollapser collapses the content overwritten with "\r" (e.g.
+// progress bars) to the final state of the line. On terminals, intermediate
+// states are still redrawn, but not more often than once per redraw interval:
+// the latest state skipped within the interval is redrawn when it expires.
+type carriageReturnCollapser struct {
+	line                    strings.Builder
+	isCarriageReturnPending bool
+	lastRedrawAt            time.Time
+	skippedLine             string
+	hasSkippedLine          bool
+}
+
+func (c *carriageReturnCollapser) collapse(data string, isTerminal bool, now time.Time) string {
+	var b strings.Builder
+
+	for _, r := range data {
+		if c.isCarriageReturnPending {
+			c.isCarriageReturnPending = false
+
+			if r != '\n' {
+				c.overwriteLine(&b, isTerminal, now)
+			}
+		}
+
+		switch r {
+		case '\r':
+			c.isCarriageReturnPending = true
+		case '\n':
+			c.hasSkippedLine = false
+			b.WriteString(c.line.String())
+			b.WriteRune(r)
+			c.line.Reset()
+		default:
+			c.line.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+func (c *carriageReturnCollapser) overwriteLine(b *strings.Builder, isTerminal bool, now time.Time) {
+	if isTerminal {
+		if now.Sub(c.lastRedrawAt) >= carriageReturnRedrawInterval {
+			b.WriteString(c.line.String())
+			b.WriteRune('\r')
+			c.lastRedrawAt = now
+			c.hasSkippedLine = false
+		} else {
+			c.skippedLine = c.line.String()
+			c.hasSkippedLine = true
+		}
+	}
+
+	c.line.Reset()
+}
+
+// redrawDelay returns the time left until the skipped line can be redrawn.
+func (c *carriageReturnCollapser) redrawDelay(now time.Time) (time.Duration, bool) {
+	if !c.hasSkippedLine {
+		return 0, false
+	}
+
+	return c.lastRedrawAt.Add(carriageReturnRedrawInterval).Sub(now), true
+}
+
+// redrawSkippedLine returns the latest line skipped within the redraw interval,
+// followed by the carriage return.
+func (c *carriageReturnCollapser) redrawSkippedLine(now time.Time) string {
+	if !c.hasSkippedLine {
+		return ""
+	}
+
+	c.hasSkippedLine = false
+	c.lastRedrawAt = now
+
+	return c.skippedLine + "\r"
+}
+
+func (c *carriageReturnCollapser) hasHeldLine() bool {
+	return c.line.Len() != 0
+}
+
+// flush returns the held line. The line is continued by the following data.
+func (c *carriageReturnCollapser) flush() string {
+	line := c.line.String()
+	c.line.Reset()
+
+	return line
+}
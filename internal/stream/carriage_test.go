@@ -0,0 +1,112 @@
+package stream
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/werf/logboek/pkg/level"
+)
+
+func TestCarriageReturnCollapser_notTerminal(t *testing.T) {
+	tests := []struct {
+		name     string
+		chunks   []string
+		expected string
+	}{
+		{"progress", []string{"10%\r50%\r100%\n"}, "100%\n"},
+		{"splitBetweenWrites", []string{"10%\r", "50%", "\r100", "%\ndone\n"}, "100%\ndone\n"},
+		{"crlf", []string{"foo\r", "\nbar\r\n"}, "foo\nbar\n"},
+		{"heldUntilNewLine", []string{"50%\r100%"}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := &carriageReturnCollapser{}
+
+			var result string
+			for _, chunk := range test.chunks {
+				result += c.collapse(chunk, false, time.Now())
+			}
+
+			if result != test.expected {
+				t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestCarriageReturnCollapser_terminalThrottling(t *testing.T) {
+	c := &carriageReturnCollapser{}
+	start := time.Now()
+
+	var result string
+	result += c.collapse("10%\r", true, start)
+	result += c.collapse("20%\r", true, start.Add(time.Millisecond))
+	result += c.collapse("30%\r", true, start.Add(carriageReturnRedrawInterval))
+	result += c.collapse("40%\r100%\n", true, start.Add(carriageReturnRedrawInterval+time.Millisecond))
+
+	if expected := "10%\r30%\r100%\n"; result != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, result)
+	}
+}
+
+func TestFormatAndLogProxyData_carriageReturnCollapsing(t *testing.T) {
+	for _, isTerminal := range []bool{false, true} {
+		var buf bytes.Buffer
+		s := newWrappingStream(&buf, 40)
+		s.isTerminal = isTerminal
+		s.EnableProxyStreamDataCarriageReturnCollapsing()
+		s.appendProcessBorder("│", nil)
+
+		s.FormatAndLogProxyData(level.Default, nil, "10%\r100%\ndone\n")
+
+		expected := "│ 100%\n│ done\n"
+		if isTerminal {
+			expected = "│ 10%\r│ 100%\n│ done\n"
+		}
+
+		if got := buf.String(); got != expected {
+			t.Errorf("isTerminal=%v\n[EXPECTED]: %q\n[GOT]: %q", isTerminal, expected, got)
+		}
+	}
+}
+
+func TestCarriageReturnCollapser_redrawSkippedLine(t *testing.T) {
+	c := &carriageReturnCollapser{}
+	start := time.Now()
+
+	// The first line is redrawn when the next line starts.
+	result := c.collapse("10%\r20%\r30%\r40", true, start)
+
+	if delay, ok := c.redrawDelay(start.Add(time.Millisecond)); !ok || delay != carriageReturnRedrawInterval-time.Millisecond {
+		t.Errorf("unexpected redraw delay %s (%v)", delay, ok)
+	}
+
+	result += c.redrawSkippedLine(start.Add(carriageReturnRedrawInterval))
+	if _, ok := c.redrawDelay(start.Add(carriageReturnRedrawInterval)); ok {
+		t.Errorf("unexpected redraw after the skipped line is redrawn")
+	}
+
+	if expected := "10%\r30%\r"; result != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, result)
+	}
+}
+
+func TestFormatAndLogProxyData_redrawOnIntervalExpiry(t *testing.T) {
+	var buf syncBuffer
+	s := newWrappingStream(&buf, 40)
+	s.isTerminal = true
+	s.EnableProxyStreamDataCarriageReturnCollapsing()
+
+	s.FormatAndLogProxyData(level.Default, nil, "10%\r20%\r30%\r40")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for buf.String() != "10%\r30%\r" && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if expected := "10%\r30%\r"; buf.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, buf.String())
+	}
+}
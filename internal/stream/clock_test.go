@@ -0,0 +1,135 @@
+package stream
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/werf/logboek/pkg/clock"
+	"github.com/werf/logboek/pkg/duration"
+)
+
+func TestSetClock(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC))
+
+	t.Run("processElapsedTime", func(t *testing.T) {
+		var buf bytes.Buffer
+		s := newWrappingStream(&buf, 40)
+		s.SetClock(fakeClock)
+
+		_ = s.logProcess("build", &LogProcessOptions{}, func() error {
+			fakeClock.Advance(1500 * time.Millisecond)
+			return nil
+		})
+
+		if expected := "┌ build\n└ build (1.50 seconds)\n"; buf.String() != expected {
+			t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, buf.String())
+		}
+	})
+
+	t.Run("prefixTime", func(t *testing.T) {
+		var buf bytes.Buffer
+		s := newWrappingStream(&buf, 40)
+		s.SetClock(fakeClock)
+		s.SetPrefixTimeFormat("15:04:05")
+		s.EnablePrefixTime()
+
+		s.FormatAndLogF(nil, false, "message\n")
+
+		if expected := "10:00:01 message\n"; buf.String() != expected {
+			t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, buf.String())
+		}
+	})
+
+	t.Run("prefixDuration", func(t *testing.T) {
+		var buf bytes.Buffer
+		s := newWrappingStream(&buf, 40)
+		s.SetClock(fakeClock)
+		s.EnablePrefixDuration()
+		fakeClock.Advance(2 * time.Second)
+
+		s.FormatAndLogF(nil, false, "message\n")
+
+		if expected := "2s           message\n"; buf.String() != expected {
+			t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, buf.String())
+		}
+	})
+
+	t.Run("gitlabSectionId", func(t *testing.T) {
+		s := newWrappingStream(&bytes.Buffer{}, 40)
+		s.SetClock(fakeClock)
+
+		if expected := "build-step_1672653603500000000"; s.gitlabCollapsibleSectionId("Build step") != expected {
+			t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, s.gitlabCollapsibleSectionId("Build step"))
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		var buf bytes.Buffer
+		s := newWrappingStream(&buf, 40)
+		s.SetClock(fakeClock)
+		s.SetClock(nil)
+
+		if s.Clock() != clock.Real {
+			t.Fatalf("expected the real clock, got %#v", s.Clock())
+		}
+
+		_ = s.logProcess("build", &LogProcessOptions{}, func() error { return nil })
+
+		if !strings.HasPrefix(buf.String(), "┌ build\n└ build (") {
+			t.Errorf("unexpected output: %q", buf.String())
+		}
+	})
+}
+
+func TestElapsedTimeFormatting(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(s *Stream)
+		elapsed   time.Duration
+		expected  string
+	}{
+		{"default", func(s *Stream) {}, 83420 * time.Millisecond, "└ build (83.42 seconds)\n"},
+		{"formatter", func(s *Stream) { s.SetElapsedTimeFormatter(duration.Short()) }, 83420 * time.Millisecond, "└ build (1m23s)\n"},
+		{"belowThreshold", func(s *Stream) { s.SetElapsedTimeThreshold(time.Second) }, 500 * time.Millisecond, "└ build\n"},
+		{"aboveThreshold", func(s *Stream) { s.SetElapsedTimeThreshold(time.Second) }, 1500 * time.Millisecond, "└ build (1.50 seconds)\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClock := clock.NewFake(time.Time{})
+
+			var buf bytes.Buffer
+			s := newWrappingStream(&buf, 40)
+			s.SetClock(fakeClock)
+			test.configure(s)
+
+			_ = s.logProcess("build", &LogProcessOptions{}, func() error {
+				fakeClock.Advance(test.elapsed)
+				return nil
+			})
+
+			if result := strings.TrimPrefix(buf.String(), "┌ build\n"); result != test.expected {
+				t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestPrefixDurationFormatting(t *testing.T) {
+	fakeClock := clock.NewFake(time.Time{})
+
+	var buf bytes.Buffer
+	s := newWrappingStream(&buf, 40)
+	s.SetClock(fakeClock)
+	s.SetPrefixDurationFormatter(duration.Clock(1))
+	s.EnablePrefixDuration()
+	fakeClock.Advance(83420 * time.Millisecond)
+
+	s.FormatAndLogF(nil, false, "message\n")
+
+	if expected := "00:01:23.4   message\n"; buf.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, buf.String())
+	}
+}
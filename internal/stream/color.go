@@ -0,0 +1,117 @@
+package stream
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/werf/logboek/internal/stream/fitter"
+)
+
+const (
+	styleModeAuto = iota
+	styleModeEnabled
+	styleModeDisabled
+)
+
+type colorState struct {
+	// detectedColorLevel is used in auto mode.
+	detectedColorLevel fitter.ColorLevel
+	// terminalColorLevel is the palette of the terminal, used when style is
+	// enabled explicitly.
+	terminalColorLevel fitter.ColorLevel
+}
+
+// DetectColorLevel detects colors support of the writers following the common
+// environment conventions: FORCE_COLOR, NO_COLOR, CLICOLOR_FORCE, CLICOLOR and
+// TERM=dumb, then the writer must be a terminal. The palette is determined by
+// COLORTERM and TERM. The output is formatted for the most capable writer and
+// adjusted by the streams of the others.
+func (s *StateAndModes) DetectColorLevel(writers ...io.Writer) {
+	s.detectedColorLevel = fitter.ColorLevelNone
+	for _, w := range writers {
+		_, isTerminal := terminalFd(w)
+		if level := detectColorLevel(isTerminal); level > s.detectedColorLevel {
+			s.detectedColorLevel = level
+		}
+	}
+
+	s.terminalColorLevel = terminalColorLevel()
+}
+
+func detectColorLevel(isTerminal bool) fitter.ColorLevel {
+	if value, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		switch strings.ToLower(value) {
+		case "0", "false", "no", "off":
+			return fitter.ColorLevelNone
+		case "2":
+			return fitter.ColorLevel256
+		case "3":
+			return fitter.ColorLevelTrueColor
+		default:
+			return terminalColorLevel()
+		}
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return fitter.ColorLevelNone
+	}
+
+	if value := os.Getenv("CLICOLOR_FORCE"); value != "" && value != "0" {
+		return terminalColorLevel()
+	}
+
+	if os.Getenv("CLICOLOR") == "0" || !isTerminal || os.Getenv("TERM") == "dumb" {
+		return fitter.ColorLevelNone
+	}
+
+	return terminalColorLevel()
+}
+
+func terminalColorLevel() fitter.ColorLevel {
+	colorTerm := strings.ToLower(os.Getenv("COLORTERM"))
+	term := strings.ToLower(os.Getenv("TERM"))
+
+	switch {
+	case strings.Contains(colorTerm, "truecolor"), strings.Contains(colorTerm, "24bit"),
+		strings.Contains(term, "truecolor"), strings.Contains(term, "direct"):
+		return fitter.ColorLevelTrueColor
+	case strings.Contains(term, "256color"):
+		return fitter.ColorLevel256
+	default:
+		return fitter.ColorLevel16
+	}
+}
+
+func (s *StateAndModes) colorLevel() fitter.ColorLevel {
+	switch s.styleMode {
+	case styleModeEnabled:
+		if s.detectedColorLevel != fitter.ColorLevelNone {
+			return s.detectedColorLevel
+		} else if s.terminalColorLevel != fitter.ColorLevelNone {
+			return s.terminalColorLevel
+		}
+
+		return fitter.ColorLevel16
+	case styleModeDisabled:
+		return fitter.ColorLevelNone
+	default:
+		return s.detectedColorLevel
+	}
+}
+
+// adjustWriterColors strips or downgrades the colors of the output in the auto
+// mode if the writer of the stream supports fewer colors than detected for the
+// state shared with the other stream. Only the colors are stripped: the other
+// escape sequences, e.g. the GitLab section markers, are kept.
+func (s *Stream) adjustWriterColors(text string) string {
+	if s.styleMode != styleModeAuto || s.writerColorLevel >= s.detectedColorLevel {
+		return text
+	}
+
+	if s.writerColorLevel == fitter.ColorLevelNone {
+		return s.writerColorStripper.Strip(text)
+	}
+
+	return fitter.DowngradeColors(text, s.writerColorLevel)
+}
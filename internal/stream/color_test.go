@@ -0,0 +1,146 @@
+package stream
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/gookit/color"
+
+	"github.com/werf/logboek/internal/stream/fitter"
+)
+
+var colorEnvNames = []string{"FORCE_COLOR", "NO_COLOR", "CLICOLOR", "CLICOLOR_FORCE", "TERM", "COLORTERM"}
+
+func setColorEnv(t *testing.T, env map[string]string) {
+	for _, name := range colorEnvNames {
+		t.Setenv(name, "")
+		_ = os.Unsetenv(name)
+	}
+
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+}
+
+func TestDetectColorLevel(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		isTerminal bool
+		expected   fitter.ColorLevel
+	}{
+		{"terminal", map[string]string{"TERM": "xterm"}, true, fitter.ColorLevel16},
+		{"terminal256", map[string]string{"TERM": "xterm-256color"}, true, fitter.ColorLevel256},
+		{"terminalTrueColor", map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, true, fitter.ColorLevelTrueColor},
+		{"notTerminal", map[string]string{"TERM": "xterm-256color"}, false, fitter.ColorLevelNone},
+		{"dumbTerminal", map[string]string{"TERM": "dumb"}, true, fitter.ColorLevelNone},
+		{"noColor", map[string]string{"TERM": "xterm", "NO_COLOR": "1"}, true, fitter.ColorLevelNone},
+		{"cliColorOff", map[string]string{"TERM": "xterm", "CLICOLOR": "0"}, true, fitter.ColorLevelNone},
+		{"cliColorForce", map[string]string{"TERM": "xterm-256color", "CLICOLOR_FORCE": "1"}, false, fitter.ColorLevel256},
+		{"forceColor", map[string]string{"FORCE_COLOR": ""}, false, fitter.ColorLevel16},
+		{"forceColor3", map[string]string{"FORCE_COLOR": "3", "NO_COLOR": "1"}, false, fitter.ColorLevelTrueColor},
+		{"forceColorOff", map[string]string{"FORCE_COLOR": "0", "TERM": "xterm"}, true, fitter.ColorLevelNone},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setColorEnv(t, test.env)
+
+			if result := detectColorLevel(test.isTerminal); result != test.expected {
+				t.Errorf("\n[EXPECTED]: %d\n[GOT]: %d", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatWithStyle_perState(t *testing.T) {
+	setColorEnv(t, map[string]string{"TERM": "xterm"})
+
+	fileState := NewStreamState()
+	fileState.DetectColorLevel(&bytes.Buffer{})
+
+	terminalState := NewStreamState()
+	terminalState.detectedColorLevel = fitter.ColorLevel16
+
+	style := color.Style{color.FgRed}
+	if result := fileState.FormatWithStyle(style, "%s", "foo"); result != "foo" {
+		t.Errorf("file:\n[EXPECTED]: %q\n[GOT]: %q", "foo", result)
+	}
+
+	if result := terminalState.FormatWithStyle(style, "%s", "foo"); result != "\x1b[31mfoo\x1b[0m" {
+		t.Errorf("terminal:\n[EXPECTED]: %q\n[GOT]: %q", "\x1b[31mfoo\x1b[0m", result)
+	}
+
+	fileState.EnableStyle()
+	if result := fileState.FormatWithStyle(style, "%s\n%s", "\x1b[38;5;46mfoo", "bar"); result != "\x1b[31m\x1b[92mfoo\x1b[0m\n\x1b[31mbar\x1b[0m" {
+		t.Errorf("file with enabled style:\n[GOT]: %q", result)
+	}
+
+	terminalState.DisableStyle()
+	if terminalState.IsStyleEnabled() {
+		t.Errorf("expected style to be disabled")
+	}
+
+	terminalState.ResetStyle()
+	if !terminalState.IsStyleEnabled() {
+		t.Errorf("expected style to be enabled after reset for terminal")
+	}
+}
+
+func TestStream_adjustWriterColors(t *testing.T) {
+	state := NewStreamState()
+	state.detectedColorLevel = fitter.ColorLevel256
+
+	var terminalBuf, pipeBuf, basicTerminalBuf bytes.Buffer
+	terminal := NewStream(&terminalBuf, state)
+	terminal.writerColorLevel = fitter.ColorLevel256
+	pipe := NewStream(&pipeBuf, state)
+	pipe.writerColorLevel = fitter.ColorLevelNone
+	basicTerminal := NewStream(&basicTerminalBuf, state)
+	basicTerminal.writerColorLevel = fitter.ColorLevel16
+
+	style := color.Style{color.FgRed}
+	for _, s := range []*Stream{terminal, pipe, basicTerminal} {
+		s.FormatAndLogF(style, false, "%s\n", "\x1b[38;5;46mfoo")
+	}
+
+	tests := []struct {
+		name     string
+		buf      *bytes.Buffer
+		expected string
+	}{
+		{"terminal", &terminalBuf, "\x1b[31m\x1b[38;5;46mfoo\x1b[0m\n"},
+		{"pipe", &pipeBuf, "foo\n"},
+		{"basicTerminal", &basicTerminalBuf, "\x1b[31m\x1b[92mfoo\x1b[0m\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.buf.String() != test.expected {
+				t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", test.expected, test.buf.String())
+			}
+		})
+	}
+
+	state.EnableStyle()
+	pipe.FormatAndLogF(style, false, "%s\n", "bar")
+	if expected := "foo\n\x1b[31mbar\x1b[0m\n"; pipeBuf.String() != expected {
+		t.Errorf("enabled style:\n[EXPECTED]: %q\n[GOT]: %q", expected, pipeBuf.String())
+	}
+}
+
+func TestStream_adjustWriterColors_keepsSectionMarkers(t *testing.T) {
+	state := NewStreamState()
+	state.detectedColorLevel = fitter.ColorLevel256
+
+	var pipeBuf bytes.Buffer
+	pipe := NewStream(&pipeBuf, state)
+	pipe.writerColorLevel = fitter.ColorLevelNone
+
+	pipe.FormatAndLogF(color.Style{color.FgRed}, false, "%s\n", "section_start:1:build\r\x1b[0Kbuild")
+
+	if expected := "section_start:1:build\r\x1b[0Kbuild\n"; pipeBuf.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, pipeBuf.String())
+	}
+}
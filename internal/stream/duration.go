@@ -0,0 +1,64 @@
+package stream
+
+import (
+	"strings"
+	"time"
+
+	"github.com/werf/logboek/pkg/duration"
+	"github.com/werf/logboek/pkg/types"
+)
+
+const prefixDurationWidth = 12
+
+var defaultElapsedTimeFormatter = duration.Seconds(2)
+
+// SetElapsedTimeFormatter sets the format of process elapsed times (nil resets
+// the default "83.42 seconds").
+func (s *StateAndModes) SetElapsedTimeFormatter(formatter types.DurationFormatter) {
+	s.elapsedTimeFormatter = formatter
+}
+
+// SetElapsedTimeThreshold hides process elapsed times shorter than the
+// threshold.
+func (s *StateAndModes) SetElapsedTimeThreshold(threshold time.Duration) {
+	s.elapsedTimeThreshold = threshold
+}
+
+// SetPrefixDurationFormatter sets the format of the duration prefix (nil
+// resets the default time.Duration.String() cut to 12 characters).
+func (s *StateAndModes) SetPrefixDurationFormatter(formatter types.DurationFormatter) {
+	s.prefixDurationFormatter = formatter
+}
+
+// formatElapsedTime returns the formatted elapsed time or false if it is below
+// the threshold.
+func (s *StateAndModes) formatElapsedTime(d time.Duration) (string, bool) {
+	if d < s.elapsedTimeThreshold {
+		return "", false
+	}
+
+	formatter := s.elapsedTimeFormatter
+	if formatter == nil {
+		formatter = defaultElapsedTimeFormatter
+	}
+
+	return formatter(d), true
+}
+
+func (s *StateAndModes) formatPrefixDuration(d time.Duration) string {
+	var result string
+	if s.prefixDurationFormatter != nil {
+		result = s.prefixDurationFormatter(d)
+	} else {
+		result = d.String()
+		if runes := []rune(result); len(runes) > prefixDurationWidth {
+			result = string(runes[:prefixDurationWidth])
+		}
+	}
+
+	if width := len([]rune(result)); width < prefixDurationWidth {
+		result += strings.Repeat(" ", prefixDurationWidth-width)
+	}
+
+	return result + " "
+}
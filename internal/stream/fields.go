@@ -0,0 +1,78 @@
+package stream
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gookit/color"
+
+	"github.com/werf/logboek/pkg/level"
+	stylePkg "github.com/werf/logboek/pkg/style"
+)
+
+// MissingFieldValue is the value of the last key without a value.
+const MissingFieldValue = "(MISSING)"
+
+// FormatFields renders the keys and values as "key=value" pairs separated by
+// spaces. Values with spaces, quotes or control characters are quoted.
+func FormatFields(keysAndValues []interface{}) string {
+	var parts []string
+	for i := 0; i < len(keysAndValues); i += 2 {
+		key := fmt.Sprint(keysAndValues[i])
+
+		value := MissingFieldValue
+		if i+1 < len(keysAndValues) {
+			value = fmt.Sprint(keysAndValues[i+1])
+		}
+
+		if value == "" || strings.ContainsAny(value, " '=") || strconv.Quote(value) != `"`+value+`"` {
+			value = strconv.Quote(value)
+		}
+
+		parts = append(parts, key+"="+value)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// formatFieldsPart returns the fields part of the process footer.
+func formatFieldsPart(fields []interface{}) string {
+	if len(fields) == 0 {
+		return ""
+	}
+
+	return " " + FormatFields(fields)
+}
+
+// FormatAndLogFWithLevelAndFields is FormatAndLogFWithLevel with the fields
+// appended to the last line of the message.
+func (s *Stream) FormatAndLogFWithLevelAndFields(lvl level.Level, style color.Style, fields []interface{}, format string, a ...interface{}) {
+	if s.IsMuted() {
+		return
+	}
+
+	s.StateAndModes.mutex.Lock()
+	defer s.StateAndModes.mutex.Unlock()
+	defer s.useCopyState()()
+
+	s.currentLevel = lvl
+	s.formatAndLogFWithFields(style, fields, format, a...)
+}
+
+func (s *Stream) formatAndLogFWithFields(style color.Style, fields []interface{}, format string, a ...interface{}) {
+	if len(fields) == 0 {
+		s.formatAndLogF(style, false, format, a...)
+		return
+	}
+
+	msg := fmt.Sprintf(format, a...)
+	body := strings.TrimSuffix(msg, "\n")
+	tail := msg[len(body):]
+
+	if body != "" {
+		s.formatAndLogF(style, true, "%s", body+" ")
+	}
+
+	s.formatAndLogF(stylePkg.Fields(), false, "%s%s", FormatFields(fields), tail)
+}
@@ -0,0 +1,40 @@
+package stream
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/werf/logboek/pkg/level"
+)
+
+func TestFormatFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		keysAndValues []interface{}
+		expected      string
+	}{
+		{"plain", []interface{}{"image", "alpine:3", "attempt", 2}, "image=alpine:3 attempt=2"},
+		{"quoted", []interface{}{"msg", "two words", "empty", "", "eq", "a=b", "nl", "a\nb"}, `msg="two words" empty="" eq="a=b" nl="a\nb"`},
+		{"missingValue", []interface{}{"key"}, "key=(MISSING)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := FormatFields(test.keysAndValues); result != test.expected {
+				t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatAndLogFWithLevelAndFields(t *testing.T) {
+	var buf bytes.Buffer
+	s := newWrappingStream(&buf, 20)
+	s.EnableStyle()
+
+	s.FormatAndLogFWithLevelAndFields(level.Default, nil, []interface{}{"k", "v"}, "line one\nline two\n")
+
+	if expected := "line one\nline two \x1b[2mk=v\x1b[0m\n"; buf.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, buf.String())
+	}
+}
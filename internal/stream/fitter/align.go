@@ -0,0 +1,181 @@
+package fitter
+
+import (
+	"strings"
+
+	"github.com/werf/logboek/pkg/types"
+)
+
+const wrappedLineMark = "↵"
+
+// FitAndAlignText wraps the text like FitText and aligns every resulting line
+// within the content width.
+func FitAndAlignText(text string, s *State, contentWidth int, markWrappedLine bool, align types.TextAlign, padToWidth bool) string {
+	var resultLines []string
+
+	paragraphs := strings.Split(text, "\n")
+	for i, paragraph := range paragraphs {
+		isLastParagraph := i == len(paragraphs)-1
+		if !isLastParagraph {
+			paragraph += "\n"
+		}
+
+		fittedParagraph := FitText(paragraph, s, contentWidth, markWrappedLine, false)
+		if !isLastParagraph {
+			fittedParagraph = strings.TrimSuffix(fittedParagraph, "\n")
+		}
+
+		lines := strings.Split(fittedParagraph, "\n")
+		for j, line := range lines {
+			isWrappedLine := j != len(lines)-1
+			resultLines = append(resultLines, alignLine(line, contentWidth, align, padToWidth, isWrappedLine, markWrappedLine && isWrappedLine))
+		}
+	}
+
+	return strings.Join(resultLines, "\n")
+}
+
+// AlignLine aligns a single line within the width. Justified line is stretched
+// to the full width.
+func AlignLine(line string, width int, align types.TextAlign, padToWidth bool) string {
+	return alignLine(line, width, align, padToWidth, true, false)
+}
+
+func alignLine(line string, width int, align types.TextAlign, padToWidth, isWrappedLine, isMarkedLine bool) string {
+	leadingControls, body, trailingControls := splitOuterControlSequences(line)
+
+	bodyWidth := width
+	if isMarkedLine {
+		body = strings.TrimSuffix(strings.TrimRight(body, " "), wrappedLineMark)
+		bodyWidth = width - 2
+	}
+
+	body = strings.TrimRight(body, " ")
+	if align == types.AlignCenter || align == types.AlignRight {
+		body = strings.TrimLeft(body, " ")
+	}
+
+	if align == types.AlignJustify && isWrappedLine {
+		body = justify(body, bodyWidth)
+	}
+
+	free := bodyWidth - VisibleWidth(body)
+	if free < 0 {
+		free = 0
+	}
+
+	var leftPadding, rightPadding int
+	switch align {
+	case types.AlignCenter:
+		leftPadding = free / 2
+		rightPadding = free - leftPadding
+	case types.AlignRight:
+		leftPadding = free
+	default:
+		rightPadding = free
+	}
+
+	if !padToWidth && !isMarkedLine {
+		rightPadding = 0
+	}
+
+	var b strings.Builder
+	b.WriteString(leadingControls)
+	b.WriteString(strings.Repeat(" ", leftPadding))
+	b.WriteString(body)
+	b.WriteString(strings.Repeat(" ", rightPadding))
+	if isMarkedLine {
+		b.WriteString(" " + wrappedLineMark)
+	}
+	b.WriteString(trailingControls)
+
+	return b.String()
+}
+
+func justify(body string, width int) string {
+	indent := body[:len(body)-len(strings.TrimLeft(body, " "))]
+
+	var words []string
+	for _, word := range strings.Split(body[len(indent):], " ") {
+		if word != "" {
+			words = append(words, word)
+		}
+	}
+
+	gaps := len(words) - 1
+	if gaps < 1 {
+		return body
+	}
+
+	extra := width - VisibleWidth(indent+strings.Join(words, " "))
+	if extra <= 0 {
+		return indent + strings.Join(words, " ")
+	}
+
+	var b strings.Builder
+	b.WriteString(indent)
+	for i, word := range words {
+		b.WriteString(word)
+		if i == gaps {
+			break
+		}
+
+		spaces := 1 + extra/gaps
+		if i < extra%gaps {
+			spaces++
+		}
+		b.WriteString(strings.Repeat(" ", spaces))
+	}
+
+	return b.String()
+}
+
+// splitOuterControlSequences separates the escape sequences at the beginning
+// and at the end of the line, so padding inherits the line style.
+func splitOuterControlSequences(line string) (string, string, string) {
+	var tokens []string
+	var isControl []bool
+
+	stripper := &ANSIStripper{}
+	tokenStart := 0
+	inSequence := false
+	for i, r := range line {
+		switch {
+		case !inSequence && r == escapeSequenceCode:
+			if tokenStart != i {
+				tokens = append(tokens, line[tokenStart:i])
+				isControl = append(isControl, false)
+			}
+
+			tokenStart = i
+			inSequence = true
+			stripper.Strip(string(r))
+		case inSequence:
+			stripper.Strip(string(r))
+			if stripper.state == ansiGroundState {
+				end := i + len(string(r))
+				tokens = append(tokens, line[tokenStart:end])
+				isControl = append(isControl, true)
+				tokenStart = end
+				inSequence = false
+			}
+		}
+	}
+
+	if tokenStart != len(line) {
+		tokens = append(tokens, line[tokenStart:])
+		isControl = append(isControl, inSequence)
+	}
+
+	first := 0
+	for first < len(tokens) && isControl[first] {
+		first++
+	}
+
+	last := len(tokens)
+	for last > first && isControl[last-1] {
+		last--
+	}
+
+	return strings.Join(tokens[:first], ""), strings.Join(tokens[first:last], ""), strings.Join(tokens[last:], "")
+}
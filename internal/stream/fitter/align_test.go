@@ -0,0 +1,71 @@
+package fitter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/werf/logboek/pkg/types"
+)
+
+func TestFitAndAlignText(t *testing.T) {
+	tests := []struct {
+		name            string
+		data            string
+		align           types.TextAlign
+		padToWidth      bool
+		markWrappedLine bool
+		expected        string
+	}{
+		{"left", "foo bar data", types.AlignLeft, false, false, "foo bar\ndata"},
+		{"leftPadded", "foo bar data", types.AlignLeft, true, false, "foo bar   \ndata      "},
+		{"center", "foo bar data", types.AlignCenter, false, false, " foo bar\n   data"},
+		{"centerPadded", "foo bar data", types.AlignCenter, true, false, " foo bar  \n   data   "},
+		{"right", "foo bar data", types.AlignRight, false, false, "   foo bar\n      data"},
+		{"justify", "foo bar data", types.AlignJustify, false, false, "foo    bar\ndata"},
+		{"justifyUneven", "a b c de fg", types.AlignJustify, false, false, "a  b  c de\nfg"},
+		{"justifyParagraphs", "a b c\nd e f g h i", types.AlignJustify, false, false, "a b c\nd  e f g h\ni"},
+		{"rightMarked", "foo bar data", types.AlignRight, false, true, " foo bar ↵\n      data"},
+		{
+			"colorPadding",
+			"\x1b[44mfoo\x1b[0m",
+			types.AlignCenter, true, false,
+			"\x1b[44m   foo    \x1b[0m",
+		},
+		{
+			"colorRestoredAfterWrap",
+			"\x1b[44mfoo bar data\x1b[0m",
+			types.AlignRight, true, false,
+			"\x1b[44m   foo bar\x1b[0m\n\x1b[44m      data\x1b[0m",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := FitAndAlignText(test.data, &State{}, contentWidth, test.markWrappedLine, test.align, test.padToWidth)
+			if test.expected != result {
+				t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestAlignLine(t *testing.T) {
+	tests := []struct {
+		align    types.TextAlign
+		expected string
+	}{
+		{types.AlignLeft, "(1.25s)"},
+		{types.AlignCenter, " (1.25s)"},
+		{types.AlignRight, "   (1.25s)"},
+		{types.AlignJustify, "(1.25s)"},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("align=%d", test.align), func(t *testing.T) {
+			result := AlignLine("(1.25s)", contentWidth, test.align, false)
+			if test.expected != result {
+				t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", test.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package fitter
+
+import (
+	"strings"
+)
+
+const (
+	ansiGroundState = iota
+	ansiEscapeState
+	ansiEscapeIntermediateState
+	ansiCSIState
+	ansiStringState
+	ansiStringEscapeState
+)
+
+const bellCode = 7
+
+// ANSIStripper removes ANSI escape sequences (CSI, OSC and other string
+// sequences, two- and three-byte escapes) from text. The parser state is kept
+// between Strip calls, so a sequence split across several chunks of a stream
+// is removed completely.
+type ANSIStripper struct {
+	state int
+}
+
+func (st *ANSIStripper) Strip(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+
+	for _, r := range text {
+		switch st.state {
+		case ansiGroundState:
+			if r == escapeSequenceCode {
+				st.state = ansiEscapeState
+			} else {
+				b.WriteRune(r)
+			}
+		case ansiEscapeState:
+			switch {
+			case r == '[':
+				st.state = ansiCSIState
+			case r == ']' || r == 'P' || r == 'X' || r == '^' || r == '_':
+				st.state = ansiStringState
+			case r >= 0x20 && r <= 0x2f:
+				st.state = ansiEscapeIntermediateState
+			default:
+				st.state = ansiGroundState
+			}
+		case ansiEscapeIntermediateState:
+			if r < 0x20 || r > 0x2f {
+				st.state = ansiGroundState
+			}
+		case ansiCSIState:
+			if r >= 0x40 && r <= 0x7e {
+				st.state = ansiGroundState
+			}
+		case ansiStringState:
+			switch r {
+			case bellCode:
+				st.state = ansiGroundState
+			case escapeSequenceCode:
+				st.state = ansiStringEscapeState
+			}
+		case ansiStringEscapeState:
+			if r == '\\' {
+				st.state = ansiGroundState
+			} else {
+				st.state = ansiStringState
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// SGRStripper removes SGR sequences (colors and text attributes) from text and
+// keeps the other escape sequences, e.g. erasing the line. An incomplete
+// sequence at the end of the text is held until the next Strip call.
+type SGRStripper struct {
+	pending string
+}
+
+func (st *SGRStripper) Strip(text string) string {
+	text = st.pending + text
+	st.pending = ""
+
+	var b strings.Builder
+	b.Grow(len(text))
+
+	for {
+		start := strings.Index(text, "\x1b[")
+		if start == -1 {
+			if strings.HasSuffix(text, "\x1b") {
+				st.pending = "\x1b"
+				text = text[:len(text)-1]
+			}
+
+			b.WriteString(text)
+			break
+		}
+
+		b.WriteString(text[:start])
+
+		end := start + 2
+		for end < len(text) && (text[end] < 0x40 || text[end] > 0x7e) {
+			end++
+		}
+
+		if end == len(text) {
+			st.pending = text[start:]
+			break
+		}
+
+		if text[end] != 'm' {
+			b.WriteString(text[start : end+1])
+		}
+
+		text = text[end+1:]
+	}
+
+	return b.String()
+}
+
+// StripANSI returns the text without ANSI escape sequences.
+func StripANSI(text string) string {
+	return (&ANSIStripper{}).Strip(text)
+}
+
+// VisibleWidth returns the number of terminal columns the text occupies as the
+// fitter counts them: two columns for East Asian wide runes, none for combining
+// marks, one for other runes, escape sequences excluded.
+func VisibleWidth(text string) int {
+	return stringTWidth(StripANSI(text))
+}
@@ -0,0 +1,79 @@
+package fitter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{"plain", "foo bar", "foo bar"},
+		{"sgr", "\x1b[1;31mfoo\x1b[0m bar", "foo bar"},
+		{"extendedColors", "\x1b[38;5;208mfoo\x1b[48;2;10;20;30m bar\x1b[m", "foo bar"},
+		{"eraseLine", "progress\r\x1b[0Kdone", "progress\rdone"},
+		{"oscWithBell", "\x1b]0;title\afoo", "foo"},
+		{"oscWithST", "\x1b]8;;http://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"charset", "\x1b(Bfoo", "foo"},
+		{"twoByteEscape", "\x1b7foo\x1b8", "foo"},
+		{"unicode", "\x1b[32mяя🛳\x1b[0m", "яя🛳"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := StripANSI(test.data)
+			if test.expected != result {
+				t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestANSIStripper_splitSequence(t *testing.T) {
+	data := "\x1b[38;5;208mfoo\x1b]0;title\a bar\x1b[0m"
+
+	for i := 1; i < len(data); i++ {
+		stripper := &ANSIStripper{}
+		result := stripper.Strip(data[:i]) + stripper.Strip(data[i:])
+		if result != "foo bar" {
+			t.Errorf("split at %d:\n[EXPECTED]: %q\n[GOT]: %q", i, "foo bar", result)
+		}
+	}
+}
+
+func TestSGRStripper(t *testing.T) {
+	data := "\x1b[0Ksection_start:1:build\r\x1b[0K\x1b[1;38;5;208mfoo\x1b[0m\x1b]0;title\a"
+	expected := "\x1b[0Ksection_start:1:build\r\x1b[0Kfoo\x1b]0;title\a"
+
+	for i := 0; i < len(data); i++ {
+		stripper := &SGRStripper{}
+		result := stripper.Strip(data[:i]) + stripper.Strip(data[i:])
+		if result != expected {
+			t.Errorf("split at %d:\n[EXPECTED]: %q\n[GOT]: %q", i, expected, result)
+		}
+	}
+}
+
+func TestVisibleWidth(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected int
+	}{
+		{"", 0},
+		{"foo", 3},
+		{"\x1b[30m" + strings.Repeat("я", 5) + "\x1b[0m", 5},
+		{"🛳 ok", 4},
+		{"🚀 ok", 5},
+		{"日本語", 6},
+		{"e\u0301", 1},
+	}
+
+	for _, test := range tests {
+		if result := VisibleWidth(test.data); result != test.expected {
+			t.Errorf("VisibleWidth(%q):\n[EXPECTED]: %d\n[GOT]: %d", test.data, test.expected, result)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package fitter
+
+import (
+	"strconv"
+	"strings"
+)
+
+type ColorLevel int
+
+const (
+	ColorLevelNone ColorLevel = iota
+	ColorLevel16
+	ColorLevel256
+	ColorLevelTrueColor
+)
+
+// basicColorsRGB is the xterm palette of the 16 basic colors.
+var basicColorsRGB = [16][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+var colorCubeSteps = [6]int{0, 95, 135, 175, 215, 255}
+
+// DowngradeColors rewrites 256-color and truecolor SGR parameters of the text to
+// the nearest colors supported at the level. Other escape sequences are kept
+// as is. Nothing is changed for ColorLevelNone and ColorLevelTrueColor.
+func DowngradeColors(text string, level ColorLevel) string {
+	if level == ColorLevelNone || level == ColorLevelTrueColor || !strings.Contains(text, "\x1b[") {
+		return text
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.Index(text, "\x1b[")
+		if start == -1 {
+			b.WriteString(text)
+			break
+		}
+
+		b.WriteString(text[:start])
+		text = text[start+2:]
+
+		end := 0
+		for end < len(text) && (text[end] >= '0' && text[end] <= '9' || text[end] == ';') {
+			end++
+		}
+
+		b.WriteString("\x1b[")
+		if end < len(text) && text[end] == 'm' {
+			b.WriteString(downgradeSGRParameters(text[:end], level))
+		} else {
+			b.WriteString(text[:end])
+		}
+
+		text = text[end:]
+	}
+
+	return b.String()
+}
+
+func downgradeSGRParameters(parameters string, level ColorLevel) string {
+	params := strings.Split(parameters, ";")
+
+	var result []string
+	for i := 0; i < len(params); i++ {
+		if (params[i] != "38" && params[i] != "48") || i+1 >= len(params) {
+			result = append(result, params[i])
+			continue
+		}
+
+		isBackground := params[i] == "48"
+
+		switch {
+		case params[i+1] == "5" && i+2 < len(params):
+			index, err := strconv.Atoi(params[i+2])
+			if err != nil || index > 255 {
+				result = append(result, params[i:i+3]...)
+			} else if level == ColorLevel256 {
+				result = append(result, params[i:i+3]...)
+			} else {
+				result = append(result, basicColorCode(basicColorIndexFrom256(index), isBackground))
+			}
+
+			i += 2
+		case params[i+1] == "2" && i+4 < len(params):
+			var rgb [3]int
+			isValid := true
+			for j := range rgb {
+				value, err := strconv.Atoi(params[i+2+j])
+				if err != nil || value > 255 {
+					isValid = false
+				}
+				rgb[j] = value
+			}
+
+			switch {
+			case !isValid:
+				result = append(result, params[i:i+5]...)
+			case level == ColorLevel256:
+				result = append(result, params[i], "5", strconv.Itoa(color256IndexFromRGB(rgb)))
+			default:
+				result = append(result, basicColorCode(nearestBasicColorIndex(rgb), isBackground))
+			}
+
+			i += 4
+		default:
+			result = append(result, params[i])
+		}
+	}
+
+	return strings.Join(result, ";")
+}
+
+func basicColorCode(index int, isBackground bool) string {
+	code := 30 + index
+	if index >= 8 {
+		code = 90 + index - 8
+	}
+
+	if isBackground {
+		code += 10
+	}
+
+	return strconv.Itoa(code)
+}
+
+func basicColorIndexFrom256(index int) int {
+	if index < 16 {
+		return index
+	}
+
+	return nearestBasicColorIndex(rgbFrom256(index))
+}
+
+func rgbFrom256(index int) [3]int {
+	if index >= 232 {
+		gray := 8 + (index-232)*10
+		return [3]int{gray, gray, gray}
+	}
+
+	index -= 16
+	return [3]int{colorCubeSteps[index/36], colorCubeSteps[index/6%6], colorCubeSteps[index%6]}
+}
+
+func color256IndexFromRGB(rgb [3]int) int {
+	var cube [3]int
+	for i, value := range rgb {
+		cube[i] = nearestColorCubeStep(value)
+	}
+
+	cubeIndex := 16 + cube[0]*36 + cube[1]*6 + cube[2]
+	cubeRGB := [3]int{colorCubeSteps[cube[0]], colorCubeSteps[cube[1]], colorCubeSteps[cube[2]]}
+
+	average := (rgb[0] + rgb[1] + rgb[2]) / 3
+	grayIndex := 23
+	if average < 8 {
+		grayIndex = 0
+	} else if average < 238 {
+		grayIndex = (average - 8 + 5) / 10
+	}
+	gray := 8 + grayIndex*10
+
+	if colorDistance(rgb, [3]int{gray, gray, gray}) < colorDistance(rgb, cubeRGB) {
+		return 232 + grayIndex
+	}
+
+	return cubeIndex
+}
+
+func nearestColorCubeStep(value int) int {
+	nearest := 0
+	for i, step := range colorCubeSteps {
+		if abs(value-step) < abs(value-colorCubeSteps[nearest]) {
+			nearest = i
+		}
+	}
+
+	return nearest
+}
+
+func nearestBasicColorIndex(rgb [3]int) int {
+	nearest := 0
+	for i, basic := range basicColorsRGB {
+		if colorDistance(rgb, basic) < colorDistance(rgb, basicColorsRGB[nearest]) {
+			nearest = i
+		}
+	}
+
+	return nearest
+}
+
+func colorDistance(a, b [3]int) int {
+	var result int
+	for i := range a {
+		result += (a[i] - b[i]) * (a[i] - b[i])
+	}
+
+	return result
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
@@ -0,0 +1,37 @@
+package fitter
+
+import (
+	"testing"
+)
+
+func TestDowngradeColors(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		level    ColorLevel
+		expected string
+	}{
+		{"basicKept", "\x1b[1;31mfoo\x1b[0m", ColorLevel16, "\x1b[1;31mfoo\x1b[0m"},
+		{"trueColorKept", "\x1b[38;2;255;135;0mfoo", ColorLevelTrueColor, "\x1b[38;2;255;135;0mfoo"},
+		{"noneKept", "\x1b[38;5;208mfoo", ColorLevelNone, "\x1b[38;5;208mfoo"},
+		{"trueColorTo256", "\x1b[38;2;255;135;0mfoo", ColorLevel256, "\x1b[38;5;208mfoo"},
+		{"trueColorGrayTo256", "\x1b[48;2;128;128;128mfoo", ColorLevel256, "\x1b[48;5;244mfoo"},
+		{"trueColorTo16", "\x1b[1;38;2;250;10;10mfoo", ColorLevel16, "\x1b[1;91mfoo"},
+		{"trueColorBackgroundTo16", "\x1b[48;2;0;0;0mfoo", ColorLevel16, "\x1b[40mfoo"},
+		{"256Kept", "\x1b[38;5;208mfoo", ColorLevel256, "\x1b[38;5;208mfoo"},
+		{"256LowIndexTo16", "\x1b[38;5;4mfoo", ColorLevel16, "\x1b[34mfoo"},
+		{"256BrightIndexTo16", "\x1b[48;5;12mfoo", ColorLevel16, "\x1b[104mfoo"},
+		{"256CubeTo16", "\x1b[38;5;46mfoo", ColorLevel16, "\x1b[92mfoo"},
+		{"otherSequencesKept", "a\x1b[2Kb\x1b[38;5;46mc", ColorLevel16, "a\x1b[2Kb\x1b[92mc"},
+		{"incompleteKept", "\x1b[38;5", ColorLevel16, "\x1b[38;5"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := DowngradeColors(test.data, test.level)
+			if test.expected != result {
+				t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", test.expected, result)
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ const (
 	escapeSequenceCode        = 27
 
 	resetColorCode = 0
+
+	tabStopWidth = 8
 )
 
 type State struct {
@@ -34,6 +36,12 @@ type wrapperState struct {
 	sequenceStack
 }
 
+// HasCachedLine reports whether an incomplete line is held by FitText called
+// with cacheIncompleteLine.
+func (ws *wrapperState) HasCachedLine() bool {
+	return ws.sequenceStack.String() != ""
+}
+
 func (ws *wrapperState) Apply(contentWidth int, markLines bool) string {
 	var result string
 
@@ -80,7 +88,7 @@ func (ws *wrapperState) splitSequenceStack(contentWidth int, markLines bool) str
 	}
 
 	lastSlice := slices[len(slices)-1]
-	if strings.HasPrefix(lastSlice, " ") {
+	if strings.HasPrefix(lastSlice, " ") && !ws.sequenceStack.lastSliceStartsWithTab {
 		lastSlice = lastSlice[1:]
 	}
 
@@ -92,7 +100,16 @@ func (ws *wrapperState) splitSequenceStack(contentWidth int, markLines bool) str
 }
 
 func (ws *wrapperState) resetSequenceStack() {
+	tabStopOffset := ws.tabStopOffset
 	ws.sequenceStack = newSequenceStack()
+	ws.tabStopOffset = tabStopOffset
+}
+
+// SetTabStopOffset sets the column of the line the content starts at, e.g. the
+// width of the prefix, borders and indent, so the tabs are expanded to the tab
+// stops of the terminal.
+func (ws *wrapperState) SetTabStopOffset(offset int) {
+	ws.tabStopOffset = offset
 }
 
 type controlSequenceState struct {
@@ -203,6 +220,8 @@ func runFitterWrapper(r rune, s *State, contentWidth int, markWrappedLine bool)
 		carriage := string(r)
 		result += s.wrapperState.Apply(contentWidth, markWrappedLine)
 		result += carriage
+	case "\t":
+		s.wrapperState.sequenceStack.WriteTab()
 	case " ":
 		s.wrapperState.sequenceStack.WritePlainData(" ")
 	default:
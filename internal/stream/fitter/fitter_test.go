@@ -234,3 +234,95 @@ func Test_markLine(t *testing.T) {
 		})
 	}
 }
+
+func TestFitText_tab(t *testing.T) {
+	runFitTextTests(t, "withoutMarkedLine_%s", false, []fitTextTest{
+		{
+			"leading",
+			"\tab",
+			"        ab",
+		},
+		{
+			"afterText",
+			"abc\td",
+			"abc     d",
+		},
+		{
+			// The tab does not continue on the next line.
+			"onTabStop",
+			"abcdefgh\ti",
+			"abcdefgh  \ni",
+		},
+		{
+			"afterWrap",
+			"abcdefgh ij\tk",
+			"abcdefgh  \nij      k",
+		},
+		{
+			"colorDoesNotShiftStop",
+			"\x1b[30mab\x1b[0m\tc",
+			"\x1b[30mab\x1b[0m      c",
+		},
+		{
+			"stopsRestartAfterNewLine",
+			"abcdef\n\tg",
+			"abcdef\n        g",
+		},
+	})
+}
+
+func TestFitText_wideRunes(t *testing.T) {
+	runFitTextTests(t, "withoutMarkedLine_%s", false, []fitTextTest{
+		{
+			"fits",
+			"日本語日本",
+			"日本語日本",
+		},
+		{
+			"wrapped",
+			"日本語日本語",
+			"日本語日本\n語",
+		},
+		{
+			// The wide rune does not fit into the last column.
+			"lastColumn",
+			"a日本語日本語",
+			"a日本語日 \n本語",
+		},
+	})
+}
+
+func TestFitText_tabStopOffset(t *testing.T) {
+	state := NewState()
+	state.SetTabStopOffset(3)
+
+	expected := "a    b\n     c"
+	if result := FitText("a\tb\n\tc", &state, 10, false, false); result != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, result)
+	}
+}
+
+func TestSanitizeControlChars(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{"plain", "foo bar", "foo bar"},
+		{"keepsFitterControls", "a\tb\nc\rd\be\x1b[0m", "a\tb\nc\rd\be\x1b[0m"},
+		{"bell", "done\a", "done␇"},
+		{"null", "a\x00b", "a␀b"},
+		{"delete", "a\x7fb", "a␡b"},
+		{"invalidUTF8", "a\xff\xfeb", "a��b"},
+		{"multibyte", "яя🛳", "яя🛳"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := SanitizeControlChars(test.data)
+			if test.expected != result {
+				t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", test.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package fitter
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	controlPicturesBase  = '␀'
+	deleteControlPicture = '␡'
+)
+
+// SanitizeControlChars replaces C0 control characters and DEL with the
+// corresponding Unicode control pictures (e.g. "\a" becomes "␇") and every byte
+// of invalid UTF-8 with the replacement character. Characters that the fitter
+// handles itself (tab, newline, carriage return, backspace and escape) are kept.
+func SanitizeControlChars(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+
+	for len(text) > 0 {
+		r, size := utf8.DecodeRuneInString(text)
+		text = text[size:]
+
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b.WriteRune(utf8.RuneError)
+		case r == '\t', r == '\n', r == '\r', r == '\b', r == escapeSequenceCode:
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteRune(controlPicturesBase + r)
+		case r == 0x7f:
+			b.WriteRune(deleteControlPicture)
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
@@ -10,6 +10,9 @@ type sequenceKind int
 const (
 	plainSequenceKind = iota
 	controlSequenceKind
+	// tabSequenceKind is a tab, expanded to spaces when the column of the
+	// visual line it is written on is known.
+	tabSequenceKind
 )
 
 // sequence holds one run of text. It has two lifecycle phases that never
@@ -24,7 +27,7 @@ type sequence struct {
 	str     string          // slice-phase view, materialized once from data
 	strSet  bool            // str materialized this cycle
 	off     int             // byte offset into str
-	twidth  int             // cached rune width of str[off:]
+	twidth  int             // cached terminal width of str[off:]
 	twValid bool
 	kind    sequenceKind
 }
@@ -83,7 +86,7 @@ func (s *sequence) TWidth() int {
 	}
 
 	if !s.twValid {
-		s.twidth = utf8.RuneCountInString(s.content())
+		s.twidth = stringTWidth(s.content())
 		s.twValid = true
 	}
 	return s.twidth
@@ -93,9 +96,9 @@ func (s *sequence) Slice(maxTWidth int) (string, int) {
 	content := s.content()
 	difference := maxTWidth - s.TWidth()
 	if difference <= 0 {
-		isASCII := s.twidth == len(content) // byte==rune => pure ASCII view
+		isASCII := s.twidth == len(content) // byte==column only for pure ASCII
 		if isASCII {
-			// ASCII fast-path: byte==rune so cutting at maxTWidth is exact and O(1);
+			// ASCII fast-path: byte==column so cutting at maxTWidth is exact and O(1);
 			// the tail of an ASCII string is ASCII, so the cache stays valid.
 			result := content[:maxTWidth]
 			s.off += maxTWidth
@@ -103,14 +106,28 @@ func (s *sequence) Slice(maxTWidth int) (string, int) {
 			return result, 0
 		}
 		// multibyte: cut on a rune boundary so emoji/wide chars never split mid-byte.
-		byteLen := 0
-		for i := 0; i < maxTWidth; i++ {
-			_, size := utf8.DecodeRuneInString(content[byteLen:])
+		// A wide rune which does not fit is left for the next slice, but the
+		// slice always takes at least one rune. Zero-width runes stay with the
+		// preceding rune.
+		byteLen, sliceTWidth := 0, 0
+		for byteLen < len(content) {
+			r, size := utf8.DecodeRuneInString(content[byteLen:])
+			rw := runeTWidth(r)
+			if rw != 0 && sliceTWidth+rw > maxTWidth && byteLen != 0 {
+				break
+			}
+
 			byteLen += size
+			sliceTWidth += rw
 		}
 		s.off += byteLen
 		s.twValid = false
-		return content[:byteLen], 0
+
+		rest := maxTWidth - sliceTWidth
+		if rest < 0 {
+			rest = 0
+		}
+		return content[:byteLen], rest
 	}
 
 	s.off = len(s.str)
@@ -128,16 +145,32 @@ func (s *sequence) IsEmpty() bool {
 
 type sequenceStack struct {
 	sequences []*sequence
+
+	// tabStopOffset is the column of the line the content starts at.
+	tabStopOffset int
+	// lastSliceStartsWithTab is set if the last slice starts with the spaces
+	// of an expanded tab, which are not a word separator.
+	lastSliceStartsWithTab bool
 }
 
 func newSequenceStack() sequenceStack {
 	return sequenceStack{}
 }
 
+// String returns the content with the tabs expanded as on one visual line.
 func (ss *sequenceStack) String() string {
 	var b strings.Builder
+	var column int
 	for _, s := range ss.sequences {
+		if s.kind == tabSequenceKind {
+			spaces := ss.tabWidth(column)
+			b.WriteString(strings.Repeat(" ", spaces))
+			column += spaces
+			continue
+		}
+
 		b.WriteString(s.String())
+		column += s.TWidth()
 	}
 
 	return b.String()
@@ -146,12 +179,27 @@ func (ss *sequenceStack) String() string {
 func (ss *sequenceStack) TWidth() int {
 	var result int
 	for _, s := range ss.sequences {
-		result += s.TWidth()
+		if s.kind == tabSequenceKind {
+			result += ss.tabWidth(result)
+		} else {
+			result += s.TWidth()
+		}
 	}
 
 	return result
 }
 
+// tabWidth returns the number of spaces moving from the column of the visual
+// line to the next tab stop.
+func (ss *sequenceStack) tabWidth(column int) int {
+	column += ss.tabStopOffset
+	if column < 0 {
+		column = 0
+	}
+
+	return tabStopWidth - column%tabStopWidth
+}
+
 func (ss *sequenceStack) CommitTopSequenceAsPlain() {
 	ss.CommitTopSequence(plainSequenceKind)
 }
@@ -186,6 +234,15 @@ func (ss *sequenceStack) WriteControlData(data string) {
 	ss.CommitTopSequenceAsControl()
 }
 
+func (ss *sequenceStack) WriteTab() {
+	if len(ss.sequences) != 0 && !ss.TopSequence().IsEmpty() {
+		ss.CommitTopSequenceAsPlain()
+	}
+
+	ss.WriteData("\t")
+	ss.CommitTopSequence(tabSequenceKind)
+}
+
 func (ss *sequenceStack) WriteData(data string) {
 	if len(ss.sequences) == 0 || ss.TopSequence().IsEmpty() {
 		ss.NewSequence(data)
@@ -241,9 +298,32 @@ func (ss *sequenceStack) Slice(sliceTWidth int) (string, int) {
 	var newSequences []*sequence
 
 	rest := sliceTWidth
+	ss.lastSliceStartsWithTab = false
 
 	var b strings.Builder
 	for ind, s := range ss.sequences {
+		if s.kind == tabSequenceKind {
+			if rest == 0 {
+				newSequences = append(newSequences, ss.sequences[ind:]...)
+				break
+			}
+
+			// The tab does not continue on the next line: it moves to the tab
+			// stop or to the end of the line.
+			spaces := ss.tabWidth(sliceTWidth - rest)
+			if spaces > rest {
+				spaces = rest
+			}
+
+			if rest == sliceTWidth {
+				ss.lastSliceStartsWithTab = true
+			}
+
+			b.WriteString(strings.Repeat(" ", spaces))
+			rest -= spaces
+			continue
+		}
+
 		if s.TWidth() == 0 {
 			b.WriteString(s.String())
 			continue
@@ -332,16 +332,20 @@ func TestSequenceStack_Slice(t *testing.T) {
 }
 
 // TestSequence_Slice_multibyteRuneBoundary pins the reported corruption bug.
-// maxTWidth is a rune count; the buggy Slice cut content[:maxTWidth] by BYTE,
+// maxTWidth is a column count; the buggy Slice cut content[:maxTWidth] by BYTE,
 // splitting a multibyte glyph mid-sequence and yielding invalid UTF-8 that
 // terminals render as U+FFFD (�). Against the unfixed code this fails on
 // both the invalid-UTF-8 check and the rune-count check.
 func TestSequence_Slice_multibyteRuneBoundary(t *testing.T) {
 	const maxTWidth = 3
-	tests := []struct{ name, data string }{
-		{"cyrillic", strings.Repeat("я", 6)},   // 2 bytes/rune
-		{"emojiShip", strings.Repeat("🛳", 6)}, // 4 bytes/rune
-		{"cjk", strings.Repeat("漢", 6)},        // 3 bytes/rune
+	tests := []struct {
+		name, data   string
+		expectedHead string
+		expectedRest int
+	}{
+		{"cyrillic", strings.Repeat("я", 6), "яяя", 0},   // 2 bytes/rune
+		{"emojiShip", strings.Repeat("🛳", 6), "🛳🛳🛳", 0}, // 4 bytes/rune
+		{"cjk", strings.Repeat("漢", 6), "漢", 1},         // 3 bytes/rune, 2 columns
 	}
 
 	for _, test := range tests {
@@ -351,8 +355,8 @@ func TestSequence_Slice_multibyteRuneBoundary(t *testing.T) {
 
 			head, rest := s.Slice(maxTWidth)
 
-			if rest != 0 {
-				t.Fatalf("rest: expected 0, got %d", rest)
+			if rest != test.expectedRest {
+				t.Fatalf("rest: expected %d, got %d", test.expectedRest, rest)
 			}
 			if !utf8.ValidString(head) {
 				t.Errorf("sliced head is not valid UTF-8 (mid-rune cut): %q", head)
@@ -360,8 +364,8 @@ func TestSequence_Slice_multibyteRuneBoundary(t *testing.T) {
 			if !utf8.ValidString(s.String()) {
 				t.Errorf("sliced tail is not valid UTF-8 (mid-rune cut): %q", s.String())
 			}
-			if n := utf8.RuneCountInString(head); n != maxTWidth {
-				t.Errorf("head rune count: expected %d, got %d (%q)", maxTWidth, n, head)
+			if head != test.expectedHead {
+				t.Errorf("head: expected %q, got %q", test.expectedHead, head)
 			}
 		})
 	}
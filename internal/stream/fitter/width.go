@@ -0,0 +1,37 @@
+package fitter
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+// runeTWidth returns the number of terminal columns the rune occupies: two for
+// East Asian wide and fullwidth runes, zero for combining marks and format
+// characters, one otherwise.
+func runeTWidth(r rune) int {
+	if r < utf8.RuneSelf {
+		return 1
+	}
+
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2
+	default:
+		return 1
+	}
+}
+
+func stringTWidth(s string) int {
+	var result int
+	for _, r := range s {
+		result += runeTWidth(r)
+	}
+
+	return result
+}
@@ -0,0 +1,133 @@
+package stream
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/werf/logboek/pkg/level"
+)
+
+type syncBuffer struct {
+	buf   bytes.Buffer
+	mutex sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.buf.String()
+}
+
+func TestStream_Flush(t *testing.T) {
+	t.Run("cachedLine", func(t *testing.T) {
+		var buf bytes.Buffer
+		s := newWrappingStream(&buf, 40)
+		s.appendProcessBorder("│", nil)
+
+		s.FormatAndLogProxyData(level.Default, nil, "Continue? [y/N] ")
+		if got := buf.String(); got != "" {
+			t.Fatalf("expected incomplete line to be cached, got %q", got)
+		}
+
+		s.Flush()
+		s.FormatAndLogProxyData(level.Default, nil, "y\nok\n")
+
+		if expected, got := "│ Continue? [y/N] y\n│ ok\n", buf.String(); got != expected {
+			t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, got)
+		}
+	})
+
+	t.Run("carriageReturnCollapsing", func(t *testing.T) {
+		var buf bytes.Buffer
+		s := newWrappingStream(&buf, 40)
+		s.DisableProxyStreamDataFormatting()
+		s.EnableProxyStreamDataCarriageReturnCollapsing()
+
+		_, _ = s.Write([]byte("10%\rPassword:"))
+		s.Flush()
+
+		if expected, got := "Password:", buf.String(); got != expected {
+			t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, got)
+		}
+	})
+}
+
+func TestStream_proxyDataFlushTimeout(t *testing.T) {
+	buf := &syncBuffer{}
+	s := newWrappingStream(buf, 40)
+	s.SetProxyStreamDataFlushTimeout(10 * time.Millisecond)
+
+	s.FormatAndLogProxyData(level.Default, nil, "Password:")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for buf.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if expected, got := "Password:", buf.String(); got != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, got)
+	}
+}
+
+func TestStream_proxyDataCachePerStream(t *testing.T) {
+	state := NewStreamState()
+	state.EnableLineWrapping()
+	state.SetWidth(40)
+
+	var outBuf, errBuf bytes.Buffer
+	out := NewStream(&outBuf, state)
+	errStream := NewStream(&errBuf, state)
+
+	out.FormatAndLogProxyData(level.Default, nil, "Password: ")
+	errStream.FormatAndLogProxyData(level.Error, nil, "warning\n")
+	errStream.Flush()
+
+	if expected := "warning\n"; errBuf.String() != expected {
+		t.Errorf("err:\n[EXPECTED]: %q\n[GOT]: %q", expected, errBuf.String())
+	}
+
+	out.Flush()
+	if expected := "Password: "; outBuf.String() != expected {
+		t.Errorf("out:\n[EXPECTED]: %q\n[GOT]: %q", expected, outBuf.String())
+	}
+}
+
+func TestStream_proxyDataFlushTimerStopped(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		stop     func(s *Stream)
+		expected string
+	}{
+		{"Flush", func(s *Stream) { s.Flush() }, "Password:"},
+		{"ResetState", func(s *Stream) { s.ResetState() }, ""},
+		{"Reset", func(s *Stream) { s.Reset() }, ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			buf := &syncBuffer{}
+			s := newWrappingStream(buf, 40)
+			s.SetProxyStreamDataFlushTimeout(20 * time.Millisecond)
+
+			s.FormatAndLogProxyData(level.Default, nil, "Password:")
+			test.stop(s)
+			time.Sleep(60 * time.Millisecond)
+
+			if got := buf.String(); got != test.expected {
+				t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", test.expected, got)
+			}
+
+			if s.proxyDataFlushTimer.Stop() {
+				t.Errorf("expected the flush timer to be stopped")
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package stream
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gookit/color"
+
+	"github.com/werf/logboek/pkg/level"
+)
+
+// FormatAndLogFWithLevelAndLabel is FormatAndLogFWithLevelAndFields with the
+// label written before each line of the message. The label is formatted with
+// the message style if the label style is not set.
+func (s *Stream) FormatAndLogFWithLevelAndLabel(lvl level.Level, style color.Style, label string, labelStyle color.Style, fields []interface{}, format string, a ...interface{}) {
+	if s.IsMuted() {
+		return
+	}
+
+	s.StateAndModes.mutex.Lock()
+	defer s.StateAndModes.mutex.Unlock()
+	defer s.useCopyState()()
+
+	s.currentLevel = lvl
+
+	msg := fmt.Sprintf(format, a...)
+	if label == "" {
+		s.formatAndLogFWithFields(style, fields, "%s", msg)
+		return
+	}
+
+	if labelStyle == nil {
+		labelStyle = style
+	}
+
+	lines := strings.SplitAfter(msg, "\n")
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	for i, line := range lines {
+		if s.isCursorOnNewLine && strings.TrimSpace(line) != "" {
+			s.formatAndLogF(labelStyle, true, "%s ", label)
+		}
+
+		// The fields are appended to the last line of the message.
+		if i == len(lines)-1 {
+			s.formatAndLogFWithFields(style, fields, "%s", line)
+		} else {
+			s.formatAndLogF(style, false, "%s", line)
+		}
+	}
+}
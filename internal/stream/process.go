@@ -8,26 +8,26 @@ import (
 	"github.com/avelino/slugify"
 	"github.com/gookit/color"
 
+	"github.com/werf/logboek/internal/stream/fitter"
 	stylePkg "github.com/werf/logboek/pkg/style"
 	"github.com/werf/logboek/pkg/types"
 )
 
 const (
-	logProcessTimeFormat        = "%.2f seconds"
 	logStateRightPartsSeparator = " "
 	progressDots                = "..."
 )
 
 func (s *Stream) NewLogBlock(manager types.ManagerInterface, format string, a ...interface{}) *LogBlock {
-	return &LogBlock{manager: manager, stream: s, title: stylePkg.None().Sprintf(format, a...), options: &LogBlockOptions{}}
+	return &LogBlock{manager: manager, stream: s, title: stylePkg.None().Sprintf(format, a...), options: &LogBlockOptions{level: manager.Level()}}
 }
 
 func (s *Stream) NewLogProcessInline(manager types.ManagerInterface, format string, a ...interface{}) *LogProcessInline {
-	return &LogProcessInline{manager: manager, stream: s, title: stylePkg.None().Sprintf(format, a...), options: &LogProcessInlineOptions{}}
+	return &LogProcessInline{manager: manager, stream: s, title: stylePkg.None().Sprintf(format, a...), options: &LogProcessInlineOptions{level: manager.Level()}}
 }
 
 func (s *Stream) NewLogProcess(manager types.ManagerInterface, format string, a ...interface{}) *LogProcess {
-	return &LogProcess{manager: manager, stream: s, title: stylePkg.None().Sprintf(format, a...), options: &LogProcessOptions{}}
+	return &LogProcess{manager: manager, stream: s, title: stylePkg.None().Sprintf(format, a...), options: &LogProcessOptions{level: manager.Level()}}
 }
 
 func (s *Stream) logBlock(blockMessage string, options *LogBlockOptions, blockFunc func() error) error {
@@ -36,8 +36,12 @@ func (s *Stream) logBlock(blockMessage string, options *LogBlockOptions, blockFu
 		style = stylePkg.None()
 	}
 
+	s.syncTerminalWidth()
+
 	titleFunc := func() error {
-		s.processAndLogLn(s.FormatWithStyle(style, blockMessage))
+		s.currentLevel = options.level
+		s.processAndLogF(s.FormatWithStyle(style, blockMessage))
+		s.FormatAndLogF(stylePkg.Fields(), false, "%s\n", formatFieldsPart(options.fields))
 		return nil
 	}
 
@@ -84,7 +88,10 @@ func (s *Stream) logProcessInline(processMessage string, options *LogProcessInli
 		style = stylePkg.None()
 	}
 
-	maxLength := s.ContentWidth() - len(" ") - len(progressDots) - len(fmt.Sprintf(logProcessTimeFormat, 1234.0))
+	s.syncTerminalWidth()
+
+	maxElapsedTime, _ := s.formatElapsedTime(1234 * time.Second)
+	maxLength := s.ContentWidth() - len(" ") - len(progressDots) - len([]rune(maxElapsedTime))
 	if maxLength < 1 {
 		processMessage = ""
 	} else if len(processMessage) > maxLength {
@@ -92,21 +99,25 @@ func (s *Stream) logProcessInline(processMessage string, options *LogProcessInli
 	}
 
 	processMessage = processMessage + " " + progressDots
-	s.FormatAndLogF(style, false, "%s", processMessage)
+	s.FormatAndLogFWithLevel(options.level, style, false, "%s", processMessage)
 
 	resultStyle := style
-	start := time.Now()
-
-	resultFormat := " (%s)\n"
+	start := s.clock.Now()
 
 	err := s.DoErrorWithIndent(processFunc)
+
+	var result string
+	if elapsedTime, ok := s.formatElapsedTime(s.clock.Now().Sub(start)); ok {
+		result = fmt.Sprintf(" (%s)", elapsedTime)
+	}
+
 	if err != nil {
 		resultStyle = color.GetStyle(stylePkg.ProcessFailName)
-		resultFormat = " (%s) FAILED\n"
+		result += " FAILED"
 	}
 
-	elapsedSeconds := fmt.Sprintf(logProcessTimeFormat, time.Since(start).Seconds())
-	s.FormatAndLogF(resultStyle, false, resultFormat, elapsedSeconds)
+	s.FormatAndLogFWithLevel(options.level, resultStyle, false, "%s", result)
+	s.FormatAndLogFWithLevel(options.level, stylePkg.Fields(), false, "%s\n", formatFieldsPart(options.fields))
 
 	return err
 }
@@ -133,6 +144,28 @@ func (s *Stream) prepareLogProcessMsgLeftPart(leftPart string, style color.Style
 	return s.FormatWithStyle(style, result)
 }
 
+// alignLogProcessStatusParts pads the first non-empty footer part, so the
+// status parts are right-aligned after the left part if the mode is enabled.
+func (s *Stream) alignLogProcessStatusParts(leftPart, timePart, fieldsPart string) (string, string) {
+	statusPart := strings.TrimLeft(timePart+fieldsPart, " ")
+	if !s.isLogProcessStatusAlignmentEnabled || statusPart == "" {
+		return timePart, fieldsPart
+	}
+
+	alignedStatusPart := fitter.AlignLine(statusPart, s.ContentWidth()-fitter.VisibleWidth(leftPart), types.AlignRight, false)
+	paddingWidth := len(alignedStatusPart) - len(statusPart)
+	if paddingWidth < len(logStateRightPartsSeparator) {
+		paddingWidth = len(logStateRightPartsSeparator)
+	}
+	padding := strings.Repeat(" ", paddingWidth)
+
+	if timePart != "" {
+		return padding + strings.TrimLeft(timePart, " "), fieldsPart
+	}
+
+	return timePart, padding + strings.TrimLeft(fieldsPart, " ")
+}
+
 func (s *Stream) logProcess(processMessage string, options *LogProcessOptions, processFunc func() error) error {
 	style := options.style
 	if options.style == nil {
@@ -142,6 +175,7 @@ func (s *Stream) logProcess(processMessage string, options *LogProcessOptions, p
 	s.logProcessStart(
 		processMessage,
 		LogProcessOptions{
+			level: options.level,
 			style: style,
 		},
 	)
@@ -171,11 +205,19 @@ func (s *Stream) logProcess(processMessage string, options *LogProcessOptions, p
 	}
 
 	if err != nil {
+		var failureReason string
+		if options.failureReasonFunc != nil {
+			failureReason = options.failureReasonFunc(err)
+		}
+
 		s.logProcessFail(
 			LogProcessOptions{
+				level:                options.level,
+				fields:               options.fields,
 				withoutLogOptionalLn: options.withoutLogOptionalLn,
 				withoutElapsedTime:   options.withoutElapsedTime,
 				style:                style,
+				failureReason:        failureReason,
 			})
 
 		return err
@@ -183,6 +225,8 @@ func (s *Stream) logProcess(processMessage string, options *LogProcessOptions, p
 
 	s.logProcessEnd(
 		LogProcessOptions{
+			level:                options.level,
+			fields:               options.fields,
 			withoutLogOptionalLn: options.withoutLogOptionalLn,
 			withoutElapsedTime:   options.withoutElapsedTime,
 			style:                style,
@@ -204,6 +248,8 @@ func (s *Stream) logProcessStart(processMessage string, options LogProcessOption
 		style = stylePkg.None()
 	}
 
+	s.syncTerminalWidth()
+	s.currentLevel = options.level
 	s.applyOptionalLn()
 
 	headerFunc := func() error {
@@ -225,7 +271,7 @@ func (s *Stream) logProcessStart(processMessage string, options LogProcessOption
 
 	s.appendProcessBorder(s.LogProcessVerticalBorderSign(), style)
 
-	logProcess := &logProcessDescriptor{StartedAt: time.Now(), Msg: processMessage, GitlabCollapsibleSectionId: currentGitlabCollapsibleSectionId}
+	logProcess := &logProcessDescriptor{StartedAt: s.clock.Now(), Msg: processMessage, GitlabCollapsibleSectionId: currentGitlabCollapsibleSectionId}
 	s.activeLogProcesses = append(s.activeLogProcesses, logProcess)
 }
 
@@ -235,6 +281,8 @@ func (s *Stream) logProcessStepEnd(processMessage string, options LogProcessOpti
 		style = stylePkg.None()
 	}
 
+	s.currentLevel = options.level
+
 	processMessageFunc := func() error {
 		return s.DoErrorWithoutIndent(func() error {
 			s.processAndLogLn(s.prepareLogProcessMsgLeftPart(processMessage, style))
@@ -293,18 +341,23 @@ func (s *Stream) logProcessEnd(options LogProcessOptions) {
 	s.activeLogProcesses = s.activeLogProcesses[:len(s.activeLogProcesses)-1]
 
 	s.DisableOptionalLn()
+	s.currentLevel = options.level
 
-	elapsedSeconds := fmt.Sprintf(logProcessTimeFormat, time.Since(logProcess.StartedAt).Seconds())
+	elapsedTime, isElapsedTimeShown := s.formatElapsedTime(s.clock.Now().Sub(logProcess.StartedAt))
 
 	footerFunc := func() error {
 		return s.DoErrorWithoutIndent(func() error {
 			timePart := ""
-			if !options.withoutElapsedTime {
-				timePart = fmt.Sprintf(" (%s)", elapsedSeconds)
+			if !options.withoutElapsedTime && isElapsedTimeShown {
+				timePart = fmt.Sprintf(" (%s)", elapsedTime)
 			}
 
-			s.processAndLogF(s.prepareLogProcessMsgLeftPart(logProcess.Msg, style, timePart))
-			s.FormatAndLogF(style, false, "%s\n", timePart)
+			fieldsPart := formatFieldsPart(options.fields)
+			leftPart := s.prepareLogProcessMsgLeftPart(logProcess.Msg, style, timePart+fieldsPart)
+			timePart, fieldsPart = s.alignLogProcessStatusParts(leftPart, timePart, fieldsPart)
+			s.processAndLogF(leftPart)
+			s.FormatAndLogF(style, false, "%s", timePart)
+			s.FormatAndLogF(stylePkg.Fields(), false, "%s\n", fieldsPart)
 
 			return nil
 		})
@@ -340,18 +393,27 @@ func (s *Stream) logProcessFail(options LogProcessOptions) {
 	s.activeLogProcesses = s.activeLogProcesses[:len(s.activeLogProcesses)-1]
 
 	s.DisableOptionalLn()
+	s.currentLevel = options.level
 
-	elapsedSeconds := fmt.Sprintf(logProcessTimeFormat, time.Since(logProcess.StartedAt).Seconds())
+	elapsedTime, isElapsedTimeShown := s.formatElapsedTime(s.clock.Now().Sub(logProcess.StartedAt))
 
 	footerFunc := func() error {
 		return s.DoErrorWithoutIndent(func() error {
 			timePart := " FAILED"
-			if !options.withoutElapsedTime {
-				timePart = fmt.Sprintf(" (%s) FAILED", elapsedSeconds)
+			if !options.withoutElapsedTime && isElapsedTimeShown {
+				timePart = fmt.Sprintf(" (%s) FAILED", elapsedTime)
+			}
+
+			if options.failureReason != "" {
+				timePart += ": " + options.failureReason
 			}
 
-			s.processAndLogF(s.prepareLogProcessMsgLeftPart(logProcess.Msg, color.GetStyle(stylePkg.ProcessFailName), timePart))
-			s.FormatAndLogF(color.GetStyle(stylePkg.ProcessFailName), false, "%s\n", timePart)
+			fieldsPart := formatFieldsPart(options.fields)
+			leftPart := s.prepareLogProcessMsgLeftPart(logProcess.Msg, color.GetStyle(stylePkg.ProcessFailName), timePart+fieldsPart)
+			timePart, fieldsPart = s.alignLogProcessStatusParts(leftPart, timePart, fieldsPart)
+			s.processAndLogF(leftPart)
+			s.FormatAndLogF(color.GetStyle(stylePkg.ProcessFailName), false, "%s", timePart)
+			s.FormatAndLogF(stylePkg.Fields(), false, "%s\n", fieldsPart)
 
 			return nil
 		})
@@ -377,7 +439,7 @@ func (s *Stream) endAllActiveProcesses() {
 }
 
 func (s *Stream) gitlabCollapsibleSectionId(processMsg string) string {
-	return fmt.Sprintf("%s_%d", strings.Replace(slugify.Slugify(processMsg), "_", "-", -1), time.Now().UnixNano())
+	return fmt.Sprintf("%s_%d", strings.Replace(slugify.Slugify(processMsg), "_", "-", -1), s.clock.Now().UnixNano())
 }
 
 func (s *Stream) shouldGitlabCollapsibleSectionBeOpened() bool {
@@ -389,9 +451,9 @@ func (s *Stream) shouldGitlabCollapsibleSectionBeClosed() bool {
 }
 
 func (s *Stream) gitlabCollapsibleSectionStart(sectionId, processMsg string) {
-	_, _ = s.logFBase("section_start:%d:%s\r\x1b[0K%s\n", time.Now().Unix(), sectionId, processMsg)
+	_, _ = s.logFBase("section_start:%d:%s\r\x1b[0K%s\n", s.clock.Now().Unix(), sectionId, processMsg)
 }
 
 func (s *Stream) gitlabCollapsibleSectionEnd(sectionId string) {
-	_, _ = s.logFBase("section_end:%d:%s\r\x1b[0K\n", time.Now().Unix(), sectionId)
+	_, _ = s.logFBase("section_end:%d:%s\r\x1b[0K\n", s.clock.Now().Unix(), sectionId)
 }
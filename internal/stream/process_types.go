@@ -3,6 +3,7 @@ package stream
 import (
 	"github.com/gookit/color"
 
+	"github.com/werf/logboek/pkg/level"
 	stylePkg "github.com/werf/logboek/pkg/style"
 	"github.com/werf/logboek/pkg/types"
 )
@@ -38,6 +39,8 @@ func (l *LogBlock) Do(f func()) {
 }
 
 func (l *LogBlock) DoError(f func() error) error {
+	f = withAcceptedLevel(l.manager, l.options.acceptedLevel, f)
+
 	if l.isDisabled {
 		return nil
 	} else if !l.manager.IsAccepted() {
@@ -54,10 +57,13 @@ func (l *LogBlock) DoError(f func() error) error {
 }
 
 type LogBlockOptions struct {
+	level                     level.Level
+	acceptedLevel             *level.Level
 	disableIfLevelNotAccepted bool
 	mute                      bool
 	withIndent                bool
 	withoutLogOptionalLn      bool
+	fields                    []interface{}
 	style                     color.Style
 }
 
@@ -81,6 +87,16 @@ func (opts *LogBlockOptions) Style(s color.Style) {
 	opts.style = s
 }
 
+// AcceptedLevel overrides the accepted level of the logger inside the block.
+func (opts *LogBlockOptions) AcceptedLevel(lvl level.Level) {
+	opts.acceptedLevel = &lvl
+}
+
+// Fields adds the fields shown in the block header and footer.
+func (opts *LogBlockOptions) Fields(keysAndValues ...interface{}) {
+	opts.fields = append(opts.fields[:len(opts.fields):len(opts.fields)], keysAndValues...)
+}
+
 type LogProcessInline struct {
 	manager    types.ManagerInterface
 	title      string
@@ -128,8 +144,10 @@ func (l *LogProcessInline) DoError(f func() error) error {
 }
 
 type LogProcessInlineOptions struct {
+	level                     level.Level
 	disableIfLevelNotAccepted bool
 	mute                      bool
+	fields                    []interface{}
 	style                     color.Style
 }
 
@@ -145,6 +163,11 @@ func (opts *LogProcessInlineOptions) Style(s color.Style) {
 	opts.style = s
 }
 
+// Fields adds the fields shown after the process result.
+func (opts *LogProcessInlineOptions) Fields(keysAndValues ...interface{}) {
+	opts.fields = append(opts.fields[:len(opts.fields):len(opts.fields)], keysAndValues...)
+}
+
 type LogProcess struct {
 	manager    types.ManagerInterface
 	title      string
@@ -186,6 +209,8 @@ func (l *LogProcess) DoError(f func() error) error {
 
 	l.isLaunched = true
 
+	f = withAcceptedLevel(l.manager, l.options.acceptedLevel, f)
+
 	if l.isDisabled {
 		return nil
 	} else if !l.manager.IsAccepted() {
@@ -264,6 +289,8 @@ func (l *LogProcess) Fail() {
 }
 
 type LogProcessOptions struct {
+	level                     level.Level
+	acceptedLevel             *level.Level
 	disableIfLevelNotAccepted bool
 	mute                      bool
 	withIndent                bool
@@ -271,7 +298,11 @@ type LogProcessOptions struct {
 	withoutElapsedTime        bool
 	infoSectionFunc           func(error)
 	successInfoSectionFunc    func()
+	failureReasonFunc         func(error) string
+	fields                    []interface{}
 	style                     color.Style
+
+	failureReason string
 }
 
 func (opts *LogProcessOptions) DisableIfLevelNotAccepted() {
@@ -302,6 +333,39 @@ func (opts *LogProcessOptions) SuccessInfoSectionFunc(f func()) {
 	opts.successInfoSectionFunc = f
 }
 
+// FailureReasonFunc sets the function describing the error returned by the
+// process body, e.g. an exit status; the description is shown in the footer.
+func (opts *LogProcessOptions) FailureReasonFunc(f func(err error) string) {
+	opts.failureReasonFunc = f
+}
+
+// Fields adds the fields shown in the process footer.
+func (opts *LogProcessOptions) Fields(keysAndValues ...interface{}) {
+	opts.fields = append(opts.fields[:len(opts.fields):len(opts.fields)], keysAndValues...)
+}
+
 func (opts *LogProcessOptions) Style(style color.Style) {
 	opts.style = style
 }
+
+// AcceptedLevel overrides the accepted level of the logger inside the process.
+func (opts *LogProcessOptions) AcceptedLevel(lvl level.Level) {
+	opts.acceptedLevel = &lvl
+}
+
+type acceptedLevelOverrider interface {
+	DoErrorWithAcceptedLevel(lvl level.Level, f func() error) error
+}
+
+// withAcceptedLevel returns the function running the body with the accepted
+// level of the manager logger overridden, if the level is set.
+func withAcceptedLevel(manager types.ManagerInterface, lvl *level.Level, f func() error) func() error {
+	overrider, ok := manager.(acceptedLevelOverrider)
+	if lvl == nil || !ok {
+		return f
+	}
+
+	return func() error {
+		return overrider.DoErrorWithAcceptedLevel(*lvl, f)
+	}
+}
@@ -0,0 +1,41 @@
+package stream
+
+import (
+	"io"
+	"sync"
+)
+
+var (
+	fdRedirects      = map[int]io.Writer{}
+	fdRedirectsMutex sync.RWMutex
+)
+
+// RedirectFd makes the streams of all loggers writing to the descriptor write
+// to the writer instead, e.g. to the saved descriptor while the original one is
+// captured. The returned function restores the previous redirect.
+func RedirectFd(fd int, w io.Writer) func() {
+	fdRedirectsMutex.Lock()
+	defer fdRedirectsMutex.Unlock()
+
+	prev, hasPrev := fdRedirects[fd]
+	fdRedirects[fd] = w
+
+	return func() {
+		fdRedirectsMutex.Lock()
+		defer fdRedirectsMutex.Unlock()
+
+		if hasPrev {
+			fdRedirects[fd] = prev
+		} else {
+			delete(fdRedirects, fd)
+		}
+	}
+}
+
+func redirectedFdWriter(fd int) (io.Writer, bool) {
+	fdRedirectsMutex.RLock()
+	defer fdRedirectsMutex.RUnlock()
+
+	w, ok := fdRedirects[fd]
+	return w, ok
+}
@@ -2,15 +2,19 @@ package stream
 
 import (
 	"fmt"
+	"hash/fnv"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gookit/color"
 
 	"github.com/werf/logboek/internal/stream/fitter"
-	stylePkg "github.com/werf/logboek/pkg/style"
+	"github.com/werf/logboek/pkg/clock"
+	"github.com/werf/logboek/pkg/level"
+	"github.com/werf/logboek/pkg/types"
 )
 
 type StateAndModes struct {
@@ -20,10 +24,15 @@ type StateAndModes struct {
 
 type copyable struct {
 	width int
+	clock types.Clock
+	// tagColumnWidth is shared by the states cloned from one another, so the
+	// aligned tags of sub-loggers have the same width.
+	tagColumnWidth *atomic.Int64
+	terminalWidthState
+	colorState
 
 	modes
 	baseState
-	fitter.State
 	cursorState
 	processState
 	tagState
@@ -32,6 +41,8 @@ type copyable struct {
 
 func NewStreamState() *StateAndModes {
 	s := &StateAndModes{}
+	s.clock = clock.Real
+	s.tagColumnWidth = &atomic.Int64{}
 	s.initModes()
 	s.initState()
 	return s
@@ -43,10 +54,9 @@ func (s *StateAndModes) initModes() {
 
 func (s *StateAndModes) initState() {
 	s.baseState = newBaseState()
-	s.State = fitter.NewState()
 	s.cursorState = newCursorState()
 	s.processState = newProcessState()
-	s.prefixState = newPrefixState()
+	s.prefixState = newPrefixState(s.clock.Now())
 }
 
 func (s *StateAndModes) reset() {
@@ -62,16 +72,24 @@ func (s *StateAndModes) resetModes() {
 	s.initModes()
 }
 
+// SubState returns the state of a sub-logger. If the terminal width is
+// tracked, the sub state follows the resizes of the terminal. The tags of the
+// state are inherited: the tag set on the sub state is nested under them.
 func (s *StateAndModes) SubState() *StateAndModes {
 	ss := s.SharedState()
+	ss.inheritedTagsCount = len(s.tags)
 	ss.width = s.ContentWidth()
+	ss.untrackTerminalWidth()
+	if s.isTerminalWidthTracked {
+		ss.trackParentTerminalWidth(s, s.width-ss.width)
+	}
+
 	return ss
 }
 
 func (s *StateAndModes) SharedState() *StateAndModes {
 	ss := s.clone()
 	ss.isOptionalLnEnabled = false
-	ss.State = fitter.State{}
 	ss.cursorState = newCursorState()
 	ss.processState = newProcessState()
 	return ss
@@ -86,26 +104,45 @@ func (s *StateAndModes) DisablePrettyLog() {
 type baseState struct {
 	indentWidth         int
 	isOptionalLnEnabled bool
+	currentLevel        level.Level
 }
 
 func newBaseState() baseState {
 	return baseState{}
 }
 
+const (
+	styleStrippingModeAuto = iota
+	styleStrippingModeEnabled
+	styleStrippingModeDisabled
+)
+
 type modes struct {
 	isMuted                            bool
-	isStyleEnabled                     bool
+	styleMode                          int
 	isLineWrappingEnabled              bool
 	isProxyStreamDataFormattingEnabled bool
 	isGitlabCollapsibleSectionsEnabled bool
 	isPrefixDurationEnabled            bool
 	isPrefixTimeEnabled                bool
 	isLogProcessBorderEnabled          bool
+	isLogProcessStatusAlignmentEnabled bool
+	isControlCharsSanitizingEnabled    bool
+	isTagAutoStyleEnabled              bool
+	isTagAlignmentEnabled              bool
+	isCallerLocationEnabled            bool
+	proxyStreamDataStyleStrippingMode  int
+
+	isProxyStreamDataCarriageReturnCollapsingEnabled bool
+	proxyStreamDataFlushTimeout                      time.Duration
+
+	elapsedTimeFormatter types.DurationFormatter
+	elapsedTimeThreshold time.Duration
 }
 
 func newModes() modes {
 	return modes{
-		isStyleEnabled:                     true,
+		styleMode:                          styleModeAuto,
 		isLineWrappingEnabled:              true,
 		isProxyStreamDataFormattingEnabled: true,
 		isGitlabCollapsibleSectionsEnabled: os.Getenv("GITLAB_CI") == "true",
@@ -142,13 +179,27 @@ func (s *StateAndModes) EnableLogProcessBorder() {
 }
 
 func (s *StateAndModes) DisableLogProcessBorder() {
-	s.isLogProcessBorderEnabled = true
+	s.isLogProcessBorderEnabled = false
 }
 
 func (s *StateAndModes) IsLogProcessBorderEnabled() bool {
 	return s.isLogProcessBorderEnabled
 }
 
+// EnableLogProcessStatusAlignment right-aligns the elapsed time, the status
+// and the fields of the process footers.
+func (s *StateAndModes) EnableLogProcessStatusAlignment() {
+	s.isLogProcessStatusAlignmentEnabled = true
+}
+
+func (s *StateAndModes) DisableLogProcessStatusAlignment() {
+	s.isLogProcessStatusAlignmentEnabled = false
+}
+
+func (s *StateAndModes) IsLogProcessStatusAlignmentEnabled() bool {
+	return s.isLogProcessStatusAlignmentEnabled
+}
+
 func (s *StateAndModes) DoWithProxyStreamDataFormatting(f func()) {
 	_ = s.doErrorWithProxyStreamDataFormatting(true, func() error {
 		f()
@@ -192,6 +243,57 @@ func (s *StateAndModes) IsProxyStreamDataFormattingEnabled() bool {
 	return s.isProxyStreamDataFormattingEnabled
 }
 
+// EnableProxyStreamDataStyleStripping removes escape sequences from the data
+// written to proxy streams. By default, the data is stripped only when style
+// is disabled.
+func (s *StateAndModes) EnableProxyStreamDataStyleStripping() {
+	s.proxyStreamDataStyleStrippingMode = styleStrippingModeEnabled
+}
+
+func (s *StateAndModes) DisableProxyStreamDataStyleStripping() {
+	s.proxyStreamDataStyleStrippingMode = styleStrippingModeDisabled
+}
+
+func (s *StateAndModes) IsProxyStreamDataStyleStrippingEnabled() bool {
+	switch s.proxyStreamDataStyleStrippingMode {
+	case styleStrippingModeEnabled:
+		return true
+	case styleStrippingModeDisabled:
+		return false
+	default:
+		return !s.IsStyleEnabled()
+	}
+}
+
+// EnableProxyStreamDataCarriageReturnCollapsing collapses the lines of proxied
+// data overwritten with "\r" (e.g. progress of curl, git or docker) to their
+// final state. Terminals still get the intermediate states, but throttled.
+func (s *StateAndModes) EnableProxyStreamDataCarriageReturnCollapsing() {
+	s.isProxyStreamDataCarriageReturnCollapsingEnabled = true
+}
+
+func (s *StateAndModes) DisableProxyStreamDataCarriageReturnCollapsing() {
+	s.isProxyStreamDataCarriageReturnCollapsingEnabled = false
+}
+
+func (s *StateAndModes) IsProxyStreamDataCarriageReturnCollapsingEnabled() bool {
+	return s.isProxyStreamDataCarriageReturnCollapsingEnabled
+}
+
+// SetProxyStreamDataFlushTimeout sets the idle time after which the incomplete
+// line of proxied data (e.g. an interactive prompt) is written without waiting
+// for the newline. Zero disables flushing by timeout.
+func (s *StateAndModes) SetProxyStreamDataFlushTimeout(timeout time.Duration) {
+	s.proxyStreamDataFlushTimeout = timeout
+}
+
+func (s *StateAndModes) ProxyStreamDataFlushTimeout() time.Duration {
+	return s.proxyStreamDataFlushTimeout
+}
+
+// EnableLineWrapping wraps the lines to the content width. The tabs are
+// expanded to spaces, as the wrapping needs the width of each line. Without
+// line wrapping the tabs are written as is and expanded by the terminal.
 func (s *StateAndModes) EnableLineWrapping() {
 	s.isLineWrappingEnabled = true
 }
@@ -205,16 +307,74 @@ func (s *StateAndModes) IsLineWrappingEnabled() bool {
 }
 
 func (s *StateAndModes) EnableStyle() {
-	color.ForceColor()
-	s.isStyleEnabled = true
+	s.styleMode = styleModeEnabled
 }
 
 func (s *StateAndModes) DisableStyle() {
-	s.isStyleEnabled = false
+	s.styleMode = styleModeDisabled
+}
+
+// ResetStyle restores the automatic detection of colors support.
+func (s *StateAndModes) ResetStyle() {
+	s.styleMode = styleModeAuto
 }
 
 func (s *StateAndModes) IsStyleEnabled() bool {
-	return s.isStyleEnabled
+	return s.colorLevel() != fitter.ColorLevelNone
+}
+
+func (s *StateAndModes) EnableControlCharsSanitizing() {
+	s.isControlCharsSanitizingEnabled = true
+}
+
+func (s *StateAndModes) DisableControlCharsSanitizing() {
+	s.isControlCharsSanitizingEnabled = false
+}
+
+func (s *StateAndModes) IsControlCharsSanitizingEnabled() bool {
+	return s.isControlCharsSanitizingEnabled
+}
+
+// EnableTagAutoStyle colors the tags without a style with a color of the
+// palette chosen by the tag value.
+func (s *StateAndModes) EnableTagAutoStyle() {
+	s.isTagAutoStyleEnabled = true
+}
+
+func (s *StateAndModes) DisableTagAutoStyle() {
+	s.isTagAutoStyleEnabled = false
+}
+
+func (s *StateAndModes) IsTagAutoStyleEnabled() bool {
+	return s.isTagAutoStyleEnabled
+}
+
+// EnableTagAlignment pads the tags to the width of the widest tag written by
+// the logger and its sub-loggers.
+func (s *StateAndModes) EnableTagAlignment() {
+	s.isTagAlignmentEnabled = true
+}
+
+func (s *StateAndModes) DisableTagAlignment() {
+	s.isTagAlignmentEnabled = false
+}
+
+func (s *StateAndModes) IsTagAlignmentEnabled() bool {
+	return s.isTagAlignmentEnabled
+}
+
+// EnableCallerLocation appends the file:line of the caller to the messages of
+// the Debug and more verbose levels.
+func (s *StateAndModes) EnableCallerLocation() {
+	s.isCallerLocationEnabled = true
+}
+
+func (s *StateAndModes) DisableCallerLocation() {
+	s.isCallerLocationEnabled = false
+}
+
+func (s *StateAndModes) IsCallerLocationEnabled() bool {
+	return s.isCallerLocationEnabled
 }
 
 func (s *StateAndModes) processService() string {
@@ -231,10 +391,28 @@ func (s *StateAndModes) Width() int {
 	return s.width
 }
 
+// SetWidth sets the fixed width: the terminal resizes are no longer followed.
 func (s *StateAndModes) SetWidth(value int) {
+	s.untrackTerminalWidth()
 	s.width = value
 }
 
+// SetClock sets the clock of elapsed times, prefix timestamps and GitLab
+// section markers. The prefix duration is counted from now. A nil clock
+// restores the real one.
+func (s *StateAndModes) SetClock(c types.Clock) {
+	if c == nil {
+		c = clock.Real
+	}
+
+	s.clock = c
+	s.prefixDurationStartTime = c.Now()
+}
+
+func (s *StateAndModes) Clock() types.Clock {
+	return s.clock
+}
+
 func (s *StateAndModes) ContentWidth() int {
 	return s.width - s.ServiceWidth()
 }
@@ -309,6 +487,13 @@ func (s *StateAndModes) DisableOptionalLn() {
 type cursorState struct {
 	isCursorOnNewLine              bool
 	isPrevCursorStateOnRemoveCaret bool
+
+	// linePrefix is the prefix rendered on the current line and nextLinePrefix
+	// is the one prepared for the next line. The width is counted with these
+	// values, so the prefix functions are called once per line.
+	linePrefix               string
+	nextLinePrefix           string
+	isNextLinePrefixPrepared bool
 }
 
 func newCursorState() cursorState {
@@ -442,13 +627,24 @@ func (s *StateAndModes) processBordersBlockWidth() int {
 }
 
 type tagState struct {
-	tagValue     string
-	tagStyle     color.Style
-	tagPartWidth int
+	tags         []tagSegment
+	tagSeparator string
+	// inheritedTagsCount is the number of the leading tags inherited from the
+	// parent state, which are not changed by SetTag and ResetTag.
+	inheritedTagsCount int
 }
 
-const tagIndentWidth = 2
+type tagSegment struct {
+	value string
+	style color.Style
+}
 
+const (
+	tagIndentWidth      = 2
+	defaultTagSeparator = " "
+)
+
+// DoWithTag adds the tag after the current tags while f is running.
 func (s *StateAndModes) DoWithTag(value string, style color.Style, f func()) {
 	_ = s.DoErrorWithTag(value, style, func() error {
 		f()
@@ -457,21 +653,26 @@ func (s *StateAndModes) DoWithTag(value string, style color.Style, f func()) {
 }
 
 func (s *StateAndModes) DoErrorWithTag(value string, style color.Style, f func() error) error {
-	savedTag := s.tagValue
-	savedStyle := s.tagStyle
-	s.SetTagWithStyle(value, style)
+	savedTags := s.tags
+	s.tags = append(s.tags[:len(s.tags):len(s.tags)], tagSegment{value: value, style: style})
 	err := f()
-	s.SetTagWithStyle(savedTag, savedStyle)
+	s.tags = savedTags
 
 	return err
 }
 
+// SetTag sets the value of the innermost tag.
 func (s *StateAndModes) SetTag(value string) {
-	s.tagValue = value
+	s.setInnermostTag(func(tag *tagSegment) {
+		tag.value = value
+	})
 }
 
+// SetTagStyle sets the style of the innermost tag.
 func (s *StateAndModes) SetTagStyle(style color.Style) {
-	s.tagStyle = style
+	s.setInnermostTag(func(tag *tagSegment) {
+		tag.style = style
+	})
 }
 
 func (s *StateAndModes) SetTagWithStyle(value string, style color.Style) {
@@ -479,27 +680,133 @@ func (s *StateAndModes) SetTagWithStyle(value string, style color.Style) {
 	s.SetTag(value)
 }
 
+// setInnermostTag changes a copy of the tags, which can be shared with the
+// cloned states.
+func (s *StateAndModes) setInnermostTag(f func(tag *tagSegment)) {
+	tags := make([]tagSegment, len(s.tags), len(s.tags)+1)
+	copy(tags, s.tags)
+	if len(tags) == s.inheritedTagsCount {
+		tags = append(tags, tagSegment{})
+	}
+
+	f(&tags[len(tags)-1])
+	s.tags = tags
+}
+
+// SetTagSeparator sets the separator between nested tags (a space by default).
+func (s *StateAndModes) SetTagSeparator(separator string) {
+	s.tagSeparator = separator
+}
+
+// ResetTag removes the tags except the ones inherited from the parent state.
+// The tag separator is kept.
 func (s *StateAndModes) ResetTag() {
-	s.tagState = tagState{}
+	s.tagState = tagState{
+		tags:               s.tags[:s.inheritedTagsCount:s.inheritedTagsCount],
+		tagSeparator:       s.tagSeparator,
+		inheritedTagsCount: s.inheritedTagsCount,
+	}
+}
+
+func (s *StateAndModes) tagSeparatorValue() string {
+	if s.tagSeparator == "" {
+		return defaultTagSeparator
+	}
+
+	return s.tagSeparator
 }
 
 func (s *StateAndModes) tagPartWidth() int {
-	if s.tagValue != "" {
-		return len(s.tagValue) + tagIndentWidth
+	width := s.tagsWidth()
+	if width == 0 {
+		return 0
 	}
 
-	return 0
+	if s.isTagAlignmentEnabled {
+		width = s.alignedTagsWidth(width)
+	}
+
+	return width + tagIndentWidth
+}
+
+func (s *StateAndModes) tagsWidth() int {
+	var width, count int
+	for _, tag := range s.tags {
+		if tag.value != "" {
+			width += fitter.VisibleWidth(tag.value)
+			count++
+		}
+	}
+
+	if count == 0 {
+		return 0
+	}
+
+	return width + (count-1)*fitter.VisibleWidth(s.tagSeparatorValue())
+}
+
+// alignedTagsWidth extends the tag column to the width if needed and returns
+// the column width.
+func (s *StateAndModes) alignedTagsWidth(width int) int {
+	for {
+		columnWidth := s.tagColumnWidth.Load()
+		if int64(width) <= columnWidth {
+			return int(columnWidth)
+		}
+
+		if s.tagColumnWidth.CompareAndSwap(columnWidth, int64(width)) {
+			return width
+		}
+	}
 }
 
 func (s *StateAndModes) formattedTag() string {
-	if len(s.tagValue) == 0 {
+	var parts []string
+	for _, tag := range s.tags {
+		if tag.value == "" {
+			continue
+		}
+
+		style := tag.style
+		if style == nil && s.isTagAutoStyleEnabled {
+			style = autoTagStyle(tag.value)
+		}
+
+		parts = append(parts, s.FormatWithStyle(style, tag.value))
+	}
+
+	if len(parts) == 0 {
 		return ""
 	}
 
-	return strings.Join([]string{
-		s.FormatWithStyle(s.tagStyle, s.tagValue),
-		strings.Repeat(" ", tagIndentWidth),
-	}, "")
+	var padding int
+	if s.isTagAlignmentEnabled {
+		width := s.tagsWidth()
+		padding = s.alignedTagsWidth(width) - width
+	}
+
+	return strings.Join(parts, s.tagSeparatorValue()) + strings.Repeat(" ", padding+tagIndentWidth)
+}
+
+// tagPalette has no red colors, which are used for errors.
+var tagPalette = []color.Style{
+	{color.FgGreen},
+	{color.FgYellow},
+	{color.FgBlue},
+	{color.FgMagenta},
+	{color.FgCyan},
+	{color.FgLightGreen},
+	{color.FgLightYellow},
+	{color.FgLightBlue},
+	{color.FgLightMagenta},
+	{color.FgLightCyan},
+}
+
+func autoTagStyle(value string) color.Style {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(value))
+
+	return tagPalette[h.Sum32()%uint32(len(tagPalette))]
 }
 
 type prefixState struct {
@@ -507,17 +814,22 @@ type prefixState struct {
 	prefixStyle             color.Style
 	prefixDurationStartTime time.Time
 	prefixTimeFormat        string
+	prefixDurationFormatter types.DurationFormatter
+	prefixFuncs             []types.PrefixFunc
+	lineNumber              int
 }
 
-func newPrefixState() prefixState {
+func newPrefixState(now time.Time) prefixState {
 	return prefixState{
-		prefixDurationStartTime: time.Now(),
+		prefixDurationStartTime: now,
 		prefixTimeFormat:        time.RFC3339,
 	}
 }
 
+// EnablePrefixDuration replaces the prefix with the time elapsed since the
+// prefix duration start time.
 func (s *StateAndModes) EnablePrefixDuration() {
-	s.disablePrefix()
+	s.DisablePrefix()
 	s.isPrefixDurationEnabled = true
 }
 
@@ -530,15 +842,16 @@ func (s *StateAndModes) DisablePrefixDuration() {
 }
 
 func (s *StateAndModes) ResetPrefixDurationStartTime() {
-	s.prefixDurationStartTime = time.Now()
+	s.prefixDurationStartTime = s.clock.Now()
 }
 
 func (s *StateAndModes) SetPrefixTimeFormat(format string) {
 	s.prefixTimeFormat = format
 }
 
+// EnablePrefixTime replaces the prefix with the current time.
 func (s *StateAndModes) EnablePrefixTime() {
-	s.disablePrefix()
+	s.DisablePrefix()
 	s.isPrefixTimeEnabled = true
 }
 
@@ -550,8 +863,9 @@ func (s *StateAndModes) DisablePrefixTime() {
 	s.isPrefixTimeEnabled = false
 }
 
+// SetPrefix replaces the prefix with the value.
 func (s *StateAndModes) SetPrefix(value string) {
-	s.disablePrefix()
+	s.DisablePrefix()
 	s.prefix = value
 }
 
@@ -559,46 +873,113 @@ func (s *StateAndModes) SetPrefixStyle(style color.Style) {
 	s.prefixStyle = style
 }
 
-func (s *StateAndModes) DisablePrefix() {
-	s.disablePrefix()
+// SetPrefixFunc replaces the prefix with the prefix function.
+func (s *StateAndModes) SetPrefixFunc(f types.PrefixFunc) {
+	s.DisablePrefix()
+	s.prefixFuncs = []types.PrefixFunc{f}
 }
 
-func (s *StateAndModes) disablePrefix() {
+// AppendPrefixFunc adds the prefix segment after the current prefix. Segments
+// are separated by a space. Unlike the setters above, which replace the whole
+// prefix including the segments, it keeps the current prefix.
+func (s *StateAndModes) AppendPrefixFunc(f types.PrefixFunc) {
+	s.resetNextLinePrefix()
+	s.prefixFuncs = append(s.prefixFuncs[:len(s.prefixFuncs):len(s.prefixFuncs)], f)
+}
+
+// DisablePrefix removes the prefix, including the prefix functions.
+func (s *StateAndModes) DisablePrefix() {
+	s.resetNextLinePrefix()
 	s.prefix = ""
 	s.isPrefixDurationEnabled = false
 	s.isPrefixTimeEnabled = false
+	s.prefixFuncs = nil
 }
 
+// formattedPrefix returns the prefix of the new line. The line rewritten after
+// a carriage return keeps its number.
 func (s *StateAndModes) formattedPrefix() string {
-	if s.preparePrefixValue() == "" {
+	value := s.preparedNextLinePrefix()
+	if !s.isPrevCursorStateOnRemoveCaret {
+		s.lineNumber++
+	}
+
+	s.linePrefix = value
+	s.isNextLinePrefixPrepared = false
+
+	if value == "" {
 		return ""
 	}
 
-	return s.FormatWithStyle(s.prefixStyle, s.preparePrefixValue())
+	return s.FormatWithStyle(s.prefixStyle, value)
+}
+
+func (s *StateAndModes) preparedNextLinePrefix() string {
+	if !s.isNextLinePrefixPrepared {
+		lineNumber := s.lineNumber
+		if !s.isPrevCursorStateOnRemoveCaret {
+			lineNumber++
+		}
+
+		s.nextLinePrefix = s.preparePrefixValueForLine(lineNumber)
+		s.isNextLinePrefixPrepared = true
+	}
+
+	return s.nextLinePrefix
+}
+
+// resetNextLinePrefix drops the prefix prepared for the next line, so it is
+// prepared again for the message being written.
+func (s *StateAndModes) resetNextLinePrefix() {
+	s.isNextLinePrefixPrepared = false
 }
 
 func (s *StateAndModes) preparePrefixValue() string {
+	if s.isCursorOnNewLine {
+		return s.preparedNextLinePrefix()
+	}
+
+	return s.linePrefix
+}
+
+func (s *StateAndModes) preparePrefixValueForLine(lineNumber int) string {
+	var value string
 	switch {
 	case s.isPrefixDurationEnabled:
-		timeString := time.Since(s.prefixDurationStartTime).String()
-		timeStringRunes := []rune(timeString)
-		if len(timeStringRunes) > 12 {
-			timeString = string(timeStringRunes[:12])
-		} else {
-			timeString += strings.Repeat(" ", 12-len(timeStringRunes))
-		}
-
-		timeString += " "
-		return timeString
+		value = s.formatPrefixDuration(s.clock.Now().Sub(s.prefixDurationStartTime))
 	case s.isPrefixTimeEnabled:
-		return time.Now().Format(s.prefixTimeFormat) + " "
+		value = s.clock.Now().Format(s.prefixTimeFormat) + " "
 	default:
-		return s.prefix
+		value = s.prefix
+	}
+
+	if len(s.prefixFuncs) == 0 {
+		return value
 	}
+
+	ctx := types.PrefixContext{
+		Level:        s.currentLevel,
+		LineNumber:   lineNumber,
+		Elapsed:      s.clock.Now().Sub(s.prefixDurationStartTime),
+		ProcessDepth: len(s.activeLogProcesses),
+	}
+
+	var segments []string
+	for _, f := range s.prefixFuncs {
+		if segment := f(ctx); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+
+	if len(segments) == 0 {
+		return value
+	}
+
+	return value + strings.Join(segments, " ") + " "
 }
 
 func (s *StateAndModes) prefixWidth() int {
-	return len([]rune(s.preparePrefixValue()))
+	return fitter.VisibleWidth(s.preparePrefixValue())
 }
 
 func (s *StateAndModes) processOptionalLn() string {
@@ -610,26 +991,30 @@ func (s *StateAndModes) processOptionalLn() string {
 
 		s.DisableOptionalLn()
 		s.isCursorOnNewLine = true
+		s.isPrevCursorStateOnRemoveCaret = false
 	}
 
 	return result
 }
 
 func (s *StateAndModes) FormatWithStyle(style color.Style, format string, a ...interface{}) string {
-	if !s.isStyleEnabled || style == nil {
-		style = stylePkg.None()
+	level := s.colorLevel()
+
+	var code string
+	if level != fitter.ColorLevelNone && style != nil {
+		code = style.String()
 	}
 
 	var resultLines []string
 	for _, line := range strings.Split(fmt.Sprintf(format, a...), "\n") {
-		if line == "" {
+		if line == "" || code == "" {
 			resultLines = append(resultLines, line)
 		} else {
-			resultLines = append(resultLines, style.Sprint(line))
+			resultLines = append(resultLines, "\x1b["+code+"m"+line+"\x1b[0m")
 		}
 	}
 
-	return strings.Join(resultLines, "\n")
+	return fitter.DowngradeColors(strings.Join(resultLines, "\n"), level)
 }
 
 func (s *StateAndModes) clone() *StateAndModes {
@@ -3,13 +3,13 @@ package stream
 import (
 	"fmt"
 	"io"
-	"os"
 	"strings"
+	"time"
 
 	"github.com/gookit/color"
-	"golang.org/x/crypto/ssh/terminal"
 
 	"github.com/werf/logboek/internal/stream/fitter"
+	"github.com/werf/logboek/pkg/level"
 	"github.com/werf/logboek/pkg/types"
 )
 
@@ -18,6 +18,39 @@ const defaultWidth = 140
 type Stream struct {
 	io.Writer
 	*StateAndModes
+
+	isTerminal  bool
+	writerFd    int
+	hasWriterFd bool
+	// fitterState holds the wrapping state and the incomplete line cached for
+	// the writer of the stream, so the lines of the streams are not mixed.
+	fitterState               fitter.State
+	cachedLineMarkWrappedLine bool
+
+	writerColorLevel    fitter.ColorLevel
+	writerColorStripper fitter.SGRStripper
+
+	proxyDataStripper          fitter.ANSIStripper
+	proxyDataCarriageCollapser carriageReturnCollapser
+	proxyDataStyle             color.Style
+	proxyDataLevel             level.Level
+	proxyDataTag               tagSegment
+	proxyDataFlushTimer        *time.Timer
+	proxyDataRedrawTimer       *time.Timer
+
+	// isLineIncomplete is set if the data last formatted by the stream does
+	// not end with a newline.
+	isLineIncomplete bool
+
+	// copyState is set for the streams writing the copies of messages.
+	copyState *copyState
+}
+
+// copyState is the cursor state and the line number of a stream writing the
+// copies of messages, kept apart from the state shared with the other streams.
+type copyState struct {
+	cursorState
+	lineNumber int
 }
 
 func NewStream(w io.Writer, state *StateAndModes) *Stream {
@@ -25,20 +58,68 @@ func NewStream(w io.Writer, state *StateAndModes) *Stream {
 		Writer:        w,
 		StateAndModes: state,
 	}
+	if f, ok := w.(fdWriter); ok {
+		s.writerFd, s.hasWriterFd = int(f.Fd()), true
+	}
+	_, s.isTerminal = terminalFd(w)
+	s.writerColorLevel = detectColorLevel(s.isTerminal)
 	s.initWidth()
 	return s
 }
 
-func (s *Stream) initWidth() {
-	f, ok := s.Writer.(*os.File)
-	if ok && terminal.IsTerminal(int(f.Fd())) {
-		width, _, err := terminal.GetSize(int(f.Fd()))
-		if err != nil {
-			panic(fmt.Sprintf("get terminal size failed: %s", err))
+// NewCopyStream returns a stream writing the copies of messages written to the
+// other streams of the state. The copies are rendered with the settings, the
+// indent and the process borders of the state, but the cursor position and the
+// line number of the copy stream are its own.
+func NewCopyStream(w io.Writer, state *StateAndModes) *Stream {
+	s := NewStream(w, state)
+	s.copyState = &copyState{cursorState: newCursorState()}
+	return s
+}
+
+// useCopyState switches the state to the cursor state of the copy stream and
+// returns the function switching it back. The caller must hold the mutex.
+func (s *Stream) useCopyState() func() {
+	if s.copyState == nil {
+		return func() {}
+	}
+
+	savedCursorState, savedLineNumber, savedOptionalLn := s.cursorState, s.lineNumber, s.isOptionalLnEnabled
+	s.cursorState, s.lineNumber, s.isOptionalLnEnabled = s.copyState.cursorState, s.copyState.lineNumber, false
+
+	return func() {
+		s.copyState.cursorState, s.copyState.lineNumber = s.cursorState, s.lineNumber
+		s.cursorState, s.lineNumber, s.isOptionalLnEnabled = savedCursorState, savedLineNumber, savedOptionalLn
+	}
+}
+
+// writer returns the writer of the saved descriptor if the descriptor of the
+// underlying writer is redirected, e.g. captured by CaptureStdFDs.
+func (s *Stream) writer() io.Writer {
+	if s.hasWriterFd {
+		if w, ok := redirectedFdWriter(s.writerFd); ok {
+			return w
 		}
+	}
+
+	return s.Writer
+}
+
+// initWidth sets the width from the COLUMNS environment variable or, if the
+// writer is a terminal, from the terminal size, which is then kept up to date
+// on resize. Otherwise, the default width is used.
+func (s *Stream) initWidth() {
+	s.untrackTerminalWidth()
 
-		if width != 0 {
+	if width, ok := columnsEnvWidth(); ok {
+		s.width = width
+		return
+	}
+
+	if fd, ok := terminalFd(s.Writer); ok {
+		if width, ok := terminalWidth(fd); ok {
 			s.width = width
+			s.trackTerminalWidth(fd)
 			return
 		}
 	}
@@ -61,16 +142,25 @@ func (s *Stream) FitText(text string, options types.FitTextOptions) string {
 		lineWidth -= s.ServiceWidth()
 	}
 
-	return fitTextWithIndent(text, lineWidth, options.ExtraIndentWidth, options.MarkWrappedLine)
+	return fitTextWithIndent(text, lineWidth, options.ExtraIndentWidth, options.MarkWrappedLine, options.Align, options.PadToWidth)
 }
 
-func fitTextWithIndent(text string, lineWidth, extraIndentWidth int, markWrappedLine bool) string {
+func fitTextWithIndent(text string, lineWidth, extraIndentWidth int, markWrappedLine bool, align types.TextAlign, padToWidth bool) string {
 	var result string
 	var resultLines []string
 
 	contentWidth := lineWidth - extraIndentWidth
 
-	fittedText := fitter.FitText(text, &fitter.State{}, contentWidth, markWrappedLine, false)
+	state := fitter.NewState()
+	state.SetTabStopOffset(extraIndentWidth)
+
+	var fittedText string
+	if align == types.AlignLeft && !padToWidth {
+		fittedText = fitter.FitText(text, &state, contentWidth, markWrappedLine, false)
+	} else {
+		fittedText = fitter.FitAndAlignText(text, &state, contentWidth, markWrappedLine, align, padToWidth)
+	}
+
 	for _, line := range strings.Split(fittedText, "\n") {
 		indent := strings.Repeat(" ", extraIndentWidth)
 		resultLines = append(resultLines, strings.Join([]string{indent, line}, ""))
@@ -90,22 +180,238 @@ func (s *Stream) FormatAndLogF(style color.Style, cacheIncompleteLine bool, form
 
 	s.StateAndModes.mutex.Lock()
 	defer s.StateAndModes.mutex.Unlock()
+	defer s.useCopyState()()
+
+	s.formatAndLogF(style, cacheIncompleteLine, format, a...)
+}
+
+// FormatAndLogFWithLevel is FormatAndLogF for the lines of the level manager.
+func (s *Stream) FormatAndLogFWithLevel(lvl level.Level, style color.Style, cacheIncompleteLine bool, format string, a ...interface{}) {
+	if s.IsMuted() {
+		return
+	}
+
+	s.StateAndModes.mutex.Lock()
+	defer s.StateAndModes.mutex.Unlock()
+	defer s.useCopyState()()
+
+	s.currentLevel = lvl
+	s.formatAndLogF(style, cacheIncompleteLine, format, a...)
+}
+
+// FormatAndLogProxyData formats the data written to a proxy stream. The
+// incomplete last line is cached until the rest of the line is written.
+func (s *Stream) FormatAndLogProxyData(lvl level.Level, style color.Style, data string) {
+	s.FormatAndLogTaggedProxyData(lvl, style, "", nil, data)
+}
+
+// FormatAndLogTaggedProxyData formats the data written to a proxy stream with
+// the tag added after the current tags. The tag is applied while the streams
+// are locked, so the writers with different tags can write concurrently.
+func (s *Stream) FormatAndLogTaggedProxyData(lvl level.Level, style color.Style, tagValue string, tagStyle color.Style, data string) {
+	if s.IsMuted() {
+		return
+	}
+
+	s.StateAndModes.mutex.Lock()
+	defer s.StateAndModes.mutex.Unlock()
+
+	s.proxyDataStyle = style
+	s.proxyDataLevel = lvl
+	s.proxyDataTag = tagSegment{value: tagValue, style: tagStyle}
+	s.currentLevel = lvl
+	defer s.useProxyDataTag()()
+	defer s.scheduleProxyDataFlush()
+	defer s.scheduleProxyDataRedraw()
+
+	data = s.prepareProxyData(data)
+	if data == "" {
+		return
+	}
+
+	s.formatAndLogF(style, true, "%s", data)
+}
+
+// Flush writes the incomplete line of proxied data held for wrapping or
+// carriage return collapsing.
+func (s *Stream) Flush() {
+	s.StateAndModes.mutex.Lock()
+	defer s.StateAndModes.mutex.Unlock()
+	defer s.useCopyState()()
+
+	s.stopProxyDataFlushTimer()
+	s.stopProxyDataRedrawTimer()
+	s.flush()
+}
+
+// FlushLine writes the incomplete line of proxied data as Flush does and ends
+// the line if the data last written by the stream does not end with a newline,
+// e.g. when the output of a command ends.
+func (s *Stream) FlushLine() {
+	s.StateAndModes.mutex.Lock()
+	defer s.StateAndModes.mutex.Unlock()
+	defer s.useCopyState()()
+
+	s.stopProxyDataFlushTimer()
+	s.stopProxyDataRedrawTimer()
+	s.flush()
+
+	if s.isLineIncomplete && !s.IsMuted() {
+		s.processAndLogF("\n")
+	}
+}
+
+func (s *Stream) flush() {
+	if s.IsMuted() {
+		return
+	}
+
+	defer s.useProxyDataTag()()
+
+	s.currentLevel = s.proxyDataLevel
+	s.resetNextLinePrefix()
+
+	if line := s.proxyDataCarriageCollapser.redrawSkippedLine(s.clock.Now()); line != "" {
+		s.logCollapsedProxyData(line)
+	}
+
+	if s.proxyDataCarriageCollapser.hasHeldLine() {
+		s.logCollapsedProxyData(s.proxyDataCarriageCollapser.flush())
+	}
+
+	if s.fitterState.HasCachedLine() {
+		s.fitterState.SetTabStopOffset(s.ServiceWidth())
+		s.processAndLogF(fitter.FitText("", &s.fitterState, s.ContentWidth(), s.cachedLineMarkWrappedLine, false))
+	}
+}
+
+func (s *Stream) logCollapsedProxyData(data string) {
+	if s.IsProxyStreamDataFormattingEnabled() {
+		s.formatAndLogF(s.proxyDataStyle, true, "%s", data)
+	} else {
+		_, _ = io.WriteString(s.writer(), data)
+	}
+}
+
+// redrawProxyData redraws the latest state of the line skipped by the carriage
+// return collapser within the redraw interval.
+func (s *Stream) redrawProxyData() {
+	s.StateAndModes.mutex.Lock()
+	defer s.StateAndModes.mutex.Unlock()
+	defer s.useCopyState()()
+
+	if s.IsMuted() {
+		return
+	}
+
+	line := s.proxyDataCarriageCollapser.redrawSkippedLine(s.clock.Now())
+	if line == "" {
+		return
+	}
+
+	defer s.useProxyDataTag()()
+
+	s.currentLevel = s.proxyDataLevel
+	s.logCollapsedProxyData(line)
+}
+
+// useProxyDataTag adds the tag of the proxied data after the current tags and
+// returns the function removing it. The caller must hold the mutex.
+func (s *Stream) useProxyDataTag() func() {
+	if s.proxyDataTag.value == "" {
+		return func() {}
+	}
+
+	savedTags := s.tags
+	s.tags = append(s.tags[:len(s.tags):len(s.tags)], s.proxyDataTag)
+
+	return func() {
+		s.tags = savedTags
+	}
+}
+
+// scheduleProxyDataRedraw starts the timer redrawing the line skipped by the
+// carriage return collapser when the redraw interval expires. The caller must
+// hold the mutex.
+func (s *Stream) scheduleProxyDataRedraw() {
+	delay, ok := s.proxyDataCarriageCollapser.redrawDelay(s.clock.Now())
+	if !ok {
+		s.stopProxyDataRedrawTimer()
+		return
+	}
+
+	if s.proxyDataRedrawTimer == nil {
+		s.proxyDataRedrawTimer = time.AfterFunc(delay, s.redrawProxyData)
+	} else {
+		s.proxyDataRedrawTimer.Reset(delay)
+	}
+}
+
+func (s *Stream) stopProxyDataRedrawTimer() {
+	if s.proxyDataRedrawTimer != nil {
+		s.proxyDataRedrawTimer.Stop()
+	}
+}
+
+func (s *Stream) stopProxyDataFlushTimer() {
+	if s.proxyDataFlushTimer != nil {
+		s.proxyDataFlushTimer.Stop()
+	}
+}
+
+// scheduleProxyDataFlush (re)starts the idle timer flushing the incomplete
+// line of proxied data. The caller must hold the mutex.
+func (s *Stream) scheduleProxyDataFlush() {
+	timeout := s.ProxyStreamDataFlushTimeout()
+	if timeout <= 0 || (!s.fitterState.HasCachedLine() && !s.proxyDataCarriageCollapser.hasHeldLine()) {
+		s.stopProxyDataFlushTimer()
+		return
+	}
+
+	if s.proxyDataFlushTimer == nil {
+		s.proxyDataFlushTimer = time.AfterFunc(timeout, s.Flush)
+	} else {
+		s.proxyDataFlushTimer.Reset(timeout)
+	}
+}
+
+func (s *Stream) prepareProxyData(data string) string {
+	if s.IsProxyStreamDataStyleStrippingEnabled() {
+		data = s.proxyDataStripper.Strip(data)
+	}
+
+	if s.IsProxyStreamDataCarriageReturnCollapsingEnabled() {
+		data = s.proxyDataCarriageCollapser.collapse(data, s.isTerminal, s.clock.Now())
+	}
+
+	return data
+}
+
+func (s *Stream) formatAndLogF(style color.Style, cacheIncompleteLine bool, format string, a ...interface{}) {
+	s.refreshTerminalWidth()
+	s.resetNextLinePrefix()
 
 	msg := s.FormatWithStyle(style, format, a...)
+	if s.IsControlCharsSanitizingEnabled() {
+		msg = fitter.SanitizeControlChars(msg)
+	}
 
 	if s.IsLineWrappingEnabled() {
+		const markWrappedLine = true
+		s.cachedLineMarkWrappedLine = markWrappedLine
+		s.fitterState.SetTabStopOffset(s.ServiceWidth())
+
 		var msgRunes = []rune(msg)
 		for len(msgRunes) >= chunkSize {
 			var chunk []rune
 			chunk, msgRunes = msgRunes[:chunkSize], msgRunes[chunkSize:]
-			s.processAndLogF(fitter.FitText(string(chunk), &s.StateAndModes.State, s.ContentWidth(), true, true))
+			s.processAndLogF(fitter.FitText(string(chunk), &s.fitterState, s.ContentWidth(), markWrappedLine, true))
 		}
 
-		s.processAndLogF(fitter.FitText(string(msgRunes), &s.StateAndModes.State, s.ContentWidth(), true, cacheIncompleteLine))
+		s.processAndLogF(fitter.FitText(string(msgRunes), &s.fitterState, s.ContentWidth(), markWrappedLine, cacheIncompleteLine))
 	} else {
 		s.processAndLogF(msg)
 	}
-
 }
 
 func (s *Stream) processAndLogLn(a ...interface{}) {
@@ -127,6 +433,10 @@ func (s *Stream) processAndLogFBase(format string, a ...interface{}) (int, error
 		msg = format
 	}
 
+	if msg != "" {
+		s.isLineIncomplete = !strings.HasSuffix(msg, "\n")
+	}
+
 	var b strings.Builder
 	for _, r := range []rune(msg) {
 		switch string(r) {
@@ -173,7 +483,7 @@ func (s *Stream) processDefault() string {
 }
 
 func (s *Stream) logFBase(format string, a ...interface{}) (int, error) {
-	return fmt.Fprintf(s.Writer, format, a...)
+	return io.WriteString(s.writer(), s.adjustWriterColors(fmt.Sprintf(format, a...)))
 }
 
 func (s *Stream) Write(data []byte) (int, error) {
@@ -184,7 +494,18 @@ func (s *Stream) Write(data []byte) (int, error) {
 	s.StateAndModes.mutex.Lock()
 	defer s.StateAndModes.mutex.Unlock()
 
-	return s.Writer.Write(data)
+	if s.IsProxyStreamDataStyleStrippingEnabled() || s.IsProxyStreamDataCarriageReturnCollapsingEnabled() {
+		defer s.scheduleProxyDataFlush()
+		defer s.scheduleProxyDataRedraw()
+
+		if _, err := io.WriteString(s.writer(), s.prepareProxyData(string(data))); err != nil {
+			return 0, err
+		}
+
+		return len(data), nil
+	}
+
+	return s.writer().Write(data)
 }
 
 func (s *Stream) applyOptionalLn() {
@@ -196,7 +517,19 @@ func (s *Stream) Reset() {
 	s.ResetModes()
 }
 
+// ResetState resets the state shared by the streams and drops the incomplete
+// line cached for the writer of the stream.
 func (s *Stream) ResetState() {
+	s.StateAndModes.mutex.Lock()
+	s.stopProxyDataFlushTimer()
+	s.stopProxyDataRedrawTimer()
+	s.fitterState = fitter.NewState()
+	s.proxyDataCarriageCollapser = carriageReturnCollapser{}
+	if s.copyState != nil {
+		s.copyState = &copyState{cursorState: newCursorState()}
+	}
+	s.StateAndModes.mutex.Unlock()
+
 	s.endAllActiveProcesses()
 	s.StateAndModes.resetState()
 }
@@ -6,6 +6,11 @@ import (
 	"io"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/werf/logboek/pkg/clock"
+	"github.com/werf/logboek/pkg/level"
+	"github.com/werf/logboek/pkg/types"
 )
 
 // newWrappingStream builds a Stream over buf with line wrapping enabled and a
@@ -72,3 +77,105 @@ func BenchmarkFormatAndLogF_longLine(b *testing.B) {
 		})
 	}
 }
+
+func TestFitText_align(t *testing.T) {
+	s := newWrappingStream(io.Discard, 12)
+
+	result := s.FitText("foo bar data", types.FitTextOptions{ExtraIndentWidth: 2, Align: types.AlignCenter, PadToWidth: true})
+	if expected := "   foo bar  \n     data   "; result != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, result)
+	}
+}
+
+func TestProxyDataStyleStripping(t *testing.T) {
+	chunks := []string{"\x1b[32mok\x1b[", "0m done\n"}
+
+	tests := []struct {
+		name      string
+		configure func(s *Stream)
+		expected  string
+	}{
+		{"styleDisabled", func(s *Stream) { s.DisableStyle() }, "ok done\n"},
+		{"styleEnabled", func(s *Stream) { s.EnableStyle() }, "\x1b[32mok\x1b[0m done\n"},
+		{"forcedWithStyleEnabled", func(s *Stream) { s.EnableStyle(); s.EnableProxyStreamDataStyleStripping() }, "ok done\n"},
+		{"disabledWithStyleDisabled", func(s *Stream) { s.DisableStyle(); s.DisableProxyStreamDataStyleStripping() }, "\x1b[32mok\x1b[0m done\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for _, formatted := range []bool{true, false} {
+				var buf bytes.Buffer
+				s := newWrappingStream(&buf, 40)
+				test.configure(s)
+
+				for _, chunk := range chunks {
+					if formatted {
+						s.FormatAndLogProxyData(level.Default, nil, chunk)
+					} else {
+						_, _ = s.Write([]byte(chunk))
+					}
+				}
+
+				if got := buf.String(); got != test.expected {
+					t.Errorf("formatted=%v\n[EXPECTED]: %q\n[GOT]: %q", formatted, test.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestLogProcessStatusAlignment(t *testing.T) {
+	fakeClock := clock.NewFake(time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	s := newWrappingStream(&buf, 30)
+	s.SetClock(fakeClock)
+	s.EnableLogProcessStatusAlignment()
+
+	_ = s.logProcess("build", &LogProcessOptions{}, func() error {
+		fakeClock.Advance(1500 * time.Millisecond)
+		return nil
+	})
+
+	_ = s.logProcess("test", &LogProcessOptions{withoutElapsedTime: true}, func() error {
+		return fmt.Errorf("error")
+	})
+
+	expected := "┌ build\n└ build         (1.50 seconds)\n\n┌ test\n└ test                  FAILED\n"
+	if buf.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, buf.String())
+	}
+}
+
+func TestDisableLogProcessBorder(t *testing.T) {
+	var buf bytes.Buffer
+	s := newWrappingStream(&buf, 30)
+	s.SetClock(clock.NewFake(time.Time{}))
+	s.EnableLogProcessBorder()
+	s.DisableLogProcessBorder()
+
+	if s.IsLogProcessBorderEnabled() {
+		t.Fatalf("expected the process border to be disabled")
+	}
+
+	_ = s.logProcess("build", &LogProcessOptions{}, func() error {
+		s.FormatAndLogF(nil, false, "message\n")
+		return nil
+	})
+
+	if expected := "build\nmessage\nbuild (0.00 seconds)\n"; buf.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, buf.String())
+	}
+}
+
+func TestFormatAndLogF_tabAfterPrefix(t *testing.T) {
+	var buf bytes.Buffer
+	s := newWrappingStream(&buf, 20)
+	s.SetPrefix("abc ")
+
+	s.FormatAndLogF(nil, false, "%s", "a\tb\n")
+
+	if expected, got := "abc a   b\n", buf.String(); got != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, got)
+	}
+}
@@ -0,0 +1,163 @@
+package stream
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gookit/color"
+)
+
+func TestDoWithTag_nested(t *testing.T) {
+	tests := []struct {
+		name          string
+		separator     string
+		expected      string
+		expectedWidth int
+	}{
+		{"defaultSeparator", "", "[stage] [worker-3]  message\n", len("[stage] [worker-3]  ")},
+		{"customSeparator", " > ", "[stage] > [worker-3]  message\n", len("[stage] > [worker-3]  ")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			s := newWrappingStream(&buf, 40)
+			s.SetTagSeparator(test.separator)
+
+			var width int
+			s.DoWithTag("[stage]", nil, func() {
+				s.DoWithTag("", nil, func() {
+					s.DoWithTag("[worker-3]", nil, func() {
+						width = s.ServiceWidth()
+						s.FormatAndLogF(nil, false, "message\n")
+					})
+				})
+			})
+
+			if buf.String() != test.expected {
+				t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", test.expected, buf.String())
+			}
+
+			if width != test.expectedWidth {
+				t.Errorf("\n[EXPECTED]: %d\n[GOT]: %d", test.expectedWidth, width)
+			}
+
+			if s.ServiceWidth() != 0 {
+				t.Errorf("expected tags to be removed, got service width %d", s.ServiceWidth())
+			}
+		})
+	}
+}
+
+func TestSetTag_innermost(t *testing.T) {
+	var buf bytes.Buffer
+	s := newWrappingStream(&buf, 40)
+	s.SetTag("base")
+
+	sub := s.SubState()
+	s.DoWithTag("inner", nil, func() {
+		s.SetTag("changed")
+		s.FormatAndLogF(nil, false, "message\n")
+	})
+	s.FormatAndLogF(nil, false, "message\n")
+
+	if expected := "base changed  message\nbase  message\n"; buf.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, buf.String())
+	}
+
+	s.SetTag("other")
+	if sub.formattedTag() != "base  " {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", "base  ", sub.formattedTag())
+	}
+}
+
+func TestSetTag_subState(t *testing.T) {
+	var buf bytes.Buffer
+	s := newWrappingStream(&buf, 40)
+	s.SetTag("[stage]")
+
+	sub := NewStream(&buf, s.SubState())
+	sub.SetTag("[worker-3]")
+	sub.FormatAndLogF(nil, false, "first\n")
+
+	sub.SetTag("[worker-4]")
+	sub.FormatAndLogF(nil, false, "second\n")
+
+	sub.ResetTag()
+	sub.FormatAndLogF(nil, false, "third\n")
+
+	expected := "[stage] [worker-3]  first\n[stage] [worker-4]  second\n[stage]  third\n"
+	if buf.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, buf.String())
+	}
+
+	if expected := "[stage]  "; s.formattedTag() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, s.formattedTag())
+	}
+}
+
+func TestResetTag_keepsSeparator(t *testing.T) {
+	var buf bytes.Buffer
+	s := newWrappingStream(&buf, 40)
+	s.SetTagSeparator(" > ")
+	s.SetTag("[stage]")
+
+	sub := NewStream(&buf, s.SubState())
+	sub.SetTag("[worker-3]")
+	sub.ResetTag()
+	sub.DoWithTag("[worker-4]", nil, func() {
+		sub.FormatAndLogF(nil, false, "message\n")
+	})
+
+	if expected := "[stage] > [worker-4]  message\n"; buf.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, buf.String())
+	}
+}
+
+func TestTagAutoStyle(t *testing.T) {
+	var buf bytes.Buffer
+	s := newWrappingStream(&buf, 40)
+	s.EnableStyle()
+	s.EnableTagAutoStyle()
+
+	for _, value := range []string{"worker-1", "worker-2", "worker-3"} {
+		style := autoTagStyle(value)
+		if style.String() != autoTagStyle(value).String() {
+			t.Errorf("expected stable style for %q", value)
+		}
+
+		for _, c := range style {
+			if c == color.FgRed || c == color.FgLightRed {
+				t.Errorf("unexpected red style for %q", value)
+			}
+		}
+
+		buf.Reset()
+		s.DoWithTag(value, nil, func() {
+			s.FormatAndLogF(nil, false, "message\n")
+		})
+
+		if expected := "\x1b[" + style.String() + "m" + value + "\x1b[0m  message\n"; buf.String() != expected {
+			t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, buf.String())
+		}
+	}
+}
+
+func TestTagAlignment(t *testing.T) {
+	var buf bytes.Buffer
+	s := newWrappingStream(&buf, 40)
+	s.EnableTagAlignment()
+
+	sub := NewStream(&buf, s.SubState())
+	sub.SetTag("worker-10")
+	sub.FormatAndLogF(nil, false, "first\n")
+
+	s.DoWithTag("w-2", nil, func() {
+		s.FormatAndLogF(nil, false, "second\n")
+	})
+	s.FormatAndLogF(nil, false, "untagged\n")
+
+	if expected := "worker-10  first\nw-2        second\nuntagged\n"; buf.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, buf.String())
+	}
+}
@@ -0,0 +1,121 @@
+package stream
+
+import (
+	"os"
+	"strconv"
+	"sync/atomic"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// terminalResizeGeneration is incremented on every terminal resize, so streams
+// that track the terminal width re-read it lazily on the next output.
+var terminalResizeGeneration atomic.Uint64
+
+type fdWriter interface {
+	Fd() uintptr
+}
+
+// terminalFd returns the file descriptor of the writer if it is a terminal.
+// Besides *os.File, any writer exposing Fd() is checked.
+func terminalFd(w interface{}) (int, bool) {
+	f, ok := w.(fdWriter)
+	if !ok {
+		return 0, false
+	}
+
+	fd := int(f.Fd())
+	return fd, terminal.IsTerminal(fd)
+}
+
+func terminalWidth(fd int) (int, bool) {
+	width, _, err := terminal.GetSize(fd)
+	if err != nil || width <= 0 {
+		return 0, false
+	}
+
+	return width, true
+}
+
+func columnsEnvWidth() (int, bool) {
+	width, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err != nil || width <= 0 {
+		return 0, false
+	}
+
+	return width, true
+}
+
+type terminalWidthState struct {
+	isTerminalWidthTracked  bool
+	terminalWidthFd         int
+	terminalWidthGeneration uint64
+
+	// terminalWidthParent is the state whose tracked width the sub state
+	// follows, narrower by the service width of the parent at the moment the
+	// sub state was created.
+	terminalWidthParent       *StateAndModes
+	terminalWidthParentOffset int
+}
+
+func (s *StateAndModes) trackTerminalWidth(fd int) {
+	s.isTerminalWidthTracked = true
+	s.terminalWidthFd = fd
+	s.terminalWidthGeneration = terminalResizeGeneration.Load()
+	watchTerminalResize()
+}
+
+func (s *StateAndModes) trackParentTerminalWidth(parent *StateAndModes, offset int) {
+	s.isTerminalWidthTracked = true
+	s.terminalWidthParent = parent
+	s.terminalWidthParentOffset = offset
+	s.terminalWidthGeneration = terminalResizeGeneration.Load()
+}
+
+func (s *StateAndModes) untrackTerminalWidth() {
+	s.terminalWidthState = terminalWidthState{}
+}
+
+// syncTerminalWidth is refreshTerminalWidth for callers not holding the mutex.
+func (s *StateAndModes) syncTerminalWidth() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.refreshTerminalWidth()
+}
+
+// refreshTerminalWidth re-reads the width of the tracked terminal if it has
+// been resized since the last check. The caller must hold the mutex.
+func (s *StateAndModes) refreshTerminalWidth() {
+	if !s.isTerminalWidthTracked {
+		return
+	}
+
+	generation := terminalResizeGeneration.Load()
+	if generation == s.terminalWidthGeneration {
+		return
+	}
+	s.terminalWidthGeneration = generation
+
+	if s.terminalWidthParent != nil {
+		if width := s.terminalWidthParent.syncedWidth() - s.terminalWidthParentOffset; width > 0 {
+			s.width = width
+		}
+
+		return
+	}
+
+	if width, ok := terminalWidth(s.terminalWidthFd); ok {
+		s.width = width
+	}
+}
+
+// syncedWidth returns the width after syncTerminalWidth. The sub states lock
+// the parent state while holding their own mutex, never the other way round.
+func (s *StateAndModes) syncedWidth() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.refreshTerminalWidth()
+	return s.width
+}
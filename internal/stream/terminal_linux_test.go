@@ -0,0 +1,83 @@
+package stream
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func openPty(t *testing.T) *os.File {
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("pty is not available: %s", err)
+	}
+	t.Cleanup(func() { _ = master.Close() })
+
+	if err := unix.IoctlSetPointerInt(int(master.Fd()), unix.TIOCSPTLCK, 0); err != nil {
+		t.Skipf("pty is not available: %s", err)
+	}
+
+	n, err := unix.IoctlGetInt(int(master.Fd()), unix.TIOCGPTN)
+	if err != nil {
+		t.Skipf("pty is not available: %s", err)
+	}
+
+	slave, err := os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("pty is not available: %s", err)
+	}
+	t.Cleanup(func() { _ = slave.Close() })
+
+	return slave
+}
+
+func setPtyWidth(t *testing.T, pty *os.File, width uint16) {
+	if err := unix.IoctlSetWinsize(int(pty.Fd()), unix.TIOCSWINSZ, &unix.Winsize{Row: 40, Col: width}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTerminalWidthTracking_resize(t *testing.T) {
+	t.Setenv("COLUMNS", "")
+
+	pty := openPty(t)
+	setPtyWidth(t, pty, 100)
+
+	s := NewStream(pty, NewStreamState())
+	if s.Width() != 100 {
+		t.Fatalf("\n[EXPECTED]: %d\n[GOT]: %d", 100, s.Width())
+	}
+
+	s.IncreaseIndent()
+	subState := s.SubState()
+	if subState.Width() != 98 {
+		t.Fatalf("\n[EXPECTED]: %d\n[GOT]: %d", 98, subState.Width())
+	}
+
+	generation := terminalResizeGeneration.Load()
+	setPtyWidth(t, pty, 60)
+	if err := syscall.Kill(os.Getpid(), syscall.SIGWINCH); err != nil {
+		t.Fatal(err)
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); terminalResizeGeneration.Load() == generation; {
+		if time.Now().After(deadline) {
+			t.Fatal("SIGWINCH has not been handled")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	s.syncTerminalWidth()
+	if s.Width() != 60 {
+		t.Errorf("\n[EXPECTED]: %d\n[GOT]: %d", 60, s.Width())
+	}
+
+	subState.syncTerminalWidth()
+	if subState.Width() != 58 {
+		t.Errorf("\n[EXPECTED]: %d\n[GOT]: %d", 58, subState.Width())
+	}
+}
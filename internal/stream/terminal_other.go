@@ -0,0 +1,6 @@
+//go:build !unix
+
+package stream
+
+// watchTerminalResize is a no-op: there is no resize signal on this platform.
+func watchTerminalResize() {}
@@ -0,0 +1,83 @@
+package stream
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+type wrappedFile struct {
+	*os.File
+}
+
+func TestNewStream_width(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	tests := []struct {
+		name     string
+		columns  string
+		writer   io.Writer
+		expected int
+	}{
+		{"columnsEnv", "77", &bytes.Buffer{}, 77},
+		{"invalidColumnsEnv", "wide", &bytes.Buffer{}, defaultWidth},
+		{"nonPositiveColumnsEnv", "0", &bytes.Buffer{}, defaultWidth},
+		{"notTerminalFile", "", w, defaultWidth},
+		{"notTerminalWrappedFile", "", wrappedFile{w}, defaultWidth},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("COLUMNS", test.columns)
+
+			s := NewStream(test.writer, NewStreamState())
+			if s.Width() != test.expected {
+				t.Errorf("\n[EXPECTED]: %d\n[GOT]: %d", test.expected, s.Width())
+			}
+
+			if s.isTerminalWidthTracked {
+				t.Errorf("expected width of non-terminal writer not to be tracked")
+			}
+		})
+	}
+}
+
+func TestRefreshTerminalWidth_getSizeFailure(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	s := NewStreamState()
+	s.SetWidth(100)
+	s.trackTerminalWidth(int(w.Fd()))
+	terminalResizeGeneration.Add(1)
+
+	s.refreshTerminalWidth()
+
+	if s.Width() != 100 {
+		t.Errorf("expected width to be kept when terminal size is not available, got %d", s.Width())
+	}
+}
+
+func TestTerminalWidthTracking_disabledByExplicitWidth(t *testing.T) {
+	s := NewStreamState()
+	s.trackTerminalWidth(0)
+
+	if s.SubState().terminalWidthParent != s {
+		t.Errorf("expected sub state to follow the parent width")
+	}
+
+	s.SetWidth(80)
+	if s.isTerminalWidthTracked {
+		t.Errorf("expected SetWidth to stop tracking the terminal width")
+	}
+}
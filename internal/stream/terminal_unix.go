@@ -0,0 +1,25 @@
+//go:build unix
+
+package stream
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+var watchTerminalResizeOnce sync.Once
+
+func watchTerminalResize() {
+	watchTerminalResizeOnce.Do(func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGWINCH)
+
+		go func() {
+			for range signals {
+				terminalResizeGeneration.Add(1)
+			}
+		}()
+	})
+}
@@ -3,6 +3,7 @@ package logboek
 import (
 	"io"
 	"os"
+	"os/exec"
 
 	"golang.org/x/net/context"
 
@@ -23,6 +24,10 @@ func init() {
 	defaultErrorAndWarnStyle := color.Style{color.FgRed, color.Bold}
 	defaultLogger.Error().SetStyle(defaultErrorAndWarnStyle)
 	defaultLogger.Warn().SetStyle(defaultErrorAndWarnStyle)
+
+	// Importing the package does not print: the invalid variables are ignored
+	// here, and ConfigureFromEnv(DefaultLogger()) returns the error.
+	_ = ConfigureFromEnv(defaultLogger)
 }
 
 func NewLogger(outStream, errStream io.Writer) types.LoggerInterface {
@@ -33,6 +38,10 @@ func DefaultLogger() types.LoggerInterface {
 	return defaultLogger
 }
 
+func Fatal() types.ManagerInterface {
+	return defaultLogger.Fatal()
+}
+
 func Error() types.ManagerInterface {
 	return defaultLogger.Error()
 }
@@ -53,6 +62,30 @@ func Debug() types.ManagerInterface {
 	return defaultLogger.Debug()
 }
 
+func Trace() types.ManagerInterface {
+	return defaultLogger.Trace()
+}
+
+func EnableLevelLabels() {
+	defaultLogger.EnableLevelLabels()
+}
+
+func DisableLevelLabels() {
+	defaultLogger.DisableLevelLabels()
+}
+
+func SetExitFunc(f func(code int)) {
+	defaultLogger.SetExitFunc(f)
+}
+
+func RegisterChannel(name string, lvl level.Level, options types.ChannelOptions) types.ManagerInterface {
+	return defaultLogger.RegisterChannel(name, lvl, options)
+}
+
+func Channel(name string) (types.ManagerInterface, bool) {
+	return defaultLogger.Channel(name)
+}
+
 func LogBlock(headerOrFormat string, a ...interface{}) types.LogBlockInterface {
 	return Default().LogBlock(headerOrFormat, a...)
 }
@@ -129,6 +162,14 @@ func IsAcceptedLevel(lvl level.Level) bool {
 	return defaultLogger.IsAcceptedLevel(lvl)
 }
 
+func DoWithAcceptedLevel(lvl level.Level, f func()) {
+	defaultLogger.DoWithAcceptedLevel(lvl, f)
+}
+
+func DoErrorWithAcceptedLevel(lvl level.Level, f func() error) error {
+	return defaultLogger.DoErrorWithAcceptedLevel(lvl, f)
+}
+
 func Streams() types.StreamsInterface {
 	return defaultLogger.Streams()
 }
@@ -157,6 +198,34 @@ func ErrStream() io.Writer {
 	return defaultLogger.ErrStream()
 }
 
+// Flush writes the incomplete lines of data written to the streams of the
+// default logger if it implements types.Flusher.
+func Flush() {
+	if f, ok := defaultLogger.(types.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func With(keysAndValues ...interface{}) types.LoggerInterface {
+	return defaultLogger.With(keysAndValues...)
+}
+
+func Component(name string) types.LoggerInterface {
+	return defaultLogger.Component(name)
+}
+
+func SetComponentLevels(spec string) error {
+	return defaultLogger.SetComponentLevels(spec)
+}
+
+func LogCommand(cmd *exec.Cmd) types.LogCommandInterface {
+	return defaultLogger.LogCommand(cmd)
+}
+
+func CaptureStdFDs() (func() error, error) {
+	return defaultLogger.CaptureStdFDs()
+}
+
 func NewSubLogger(outStream, errStream io.Writer) types.LoggerInterface {
 	return defaultLogger.NewSubLogger(outStream, errStream)
 }
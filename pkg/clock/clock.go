@@ -0,0 +1,50 @@
+// Package clock provides the clocks for Streams().SetClock: the real one and a
+// fake one making timing output reproducible in tests.
+package clock
+
+import (
+	"sync"
+	"time"
+
+	"github.com/werf/logboek/pkg/types"
+)
+
+// Real is the system clock.
+var Real types.Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// Fake is a clock that only moves when advanced or set.
+type Fake struct {
+	now   time.Time
+	mutex sync.Mutex
+}
+
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (c *Fake) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *Fake) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func (c *Fake) Set(now time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = now
+}
@@ -0,0 +1,21 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2023, 1, 2, 10, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+
+	c.Advance(1500 * time.Millisecond)
+	if expected := start.Add(1500 * time.Millisecond); !c.Now().Equal(expected) {
+		t.Errorf("\n[EXPECTED]: %s\n[GOT]: %s", expected, c.Now())
+	}
+
+	c.Set(start)
+	if !c.Now().Equal(start) {
+		t.Errorf("\n[EXPECTED]: %s\n[GOT]: %s", start, c.Now())
+	}
+}
@@ -0,0 +1,74 @@
+// Package duration provides formatters for Streams().SetElapsedTimeFormatter
+// and Streams().SetPrefixDurationFormatter.
+package duration
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/werf/logboek/pkg/types"
+)
+
+// Seconds formats the duration in seconds with the number of decimals:
+// "83.42 seconds". Seconds(2) is the default elapsed time format.
+func Seconds(decimals int) types.DurationFormatter {
+	return func(d time.Duration) string {
+		return fmt.Sprintf("%.*f seconds", decimals, d.Seconds())
+	}
+}
+
+// Short formats the duration rounded to seconds: "1h2m3s", "1m23s", "4s".
+// Durations under a second are rounded to milliseconds: "450ms".
+func Short() types.DurationFormatter {
+	return func(d time.Duration) string {
+		if d < time.Second {
+			return d.Round(time.Millisecond).String()
+		}
+
+		return d.Round(time.Second).String()
+	}
+}
+
+// Clock formats the duration as a clock with the number of decimals of
+// seconds: "00:01:23.4".
+func Clock(decimals int) types.DurationFormatter {
+	return func(d time.Duration) string {
+		hours := d / time.Hour
+		minutes := (d % time.Hour) / time.Minute
+		seconds := (d % time.Minute).Seconds()
+
+		width := 2
+		if decimals > 0 {
+			width += decimals + 1
+		}
+
+		result := fmt.Sprintf("%02d:%02d:%0*.*f", hours, minutes, width, decimals, seconds)
+
+		// Rounding up to the full minute, e.g. 59.96s with 1 decimal.
+		if strings.HasSuffix(strings.SplitN(result, ".", 2)[0], ":60") {
+			return Clock(decimals)(d.Round(time.Minute))
+		}
+
+		return result
+	}
+}
+
+// Compact formats the duration with the largest units: "450ms", "1.2s",
+// "1m23s", "1h02m".
+func Compact() types.DurationFormatter {
+	return func(d time.Duration) string {
+		switch {
+		case d < time.Second:
+			return fmt.Sprintf("%dms", d.Milliseconds())
+		case d.Round(100*time.Millisecond) < time.Minute:
+			return fmt.Sprintf("%.1fs", d.Seconds())
+		case d.Round(time.Second) < time.Hour:
+			d = d.Round(time.Second)
+			return fmt.Sprintf("%dm%02ds", d/time.Minute, (d%time.Minute)/time.Second)
+		default:
+			d = d.Round(time.Minute)
+			return fmt.Sprintf("%dh%02dm", d/time.Hour, (d%time.Hour)/time.Minute)
+		}
+	}
+}
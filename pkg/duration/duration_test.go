@@ -0,0 +1,40 @@
+package duration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/werf/logboek/pkg/types"
+)
+
+func TestFormatters(t *testing.T) {
+	tests := []struct {
+		name      string
+		formatter types.DurationFormatter
+		d         time.Duration
+		expected  string
+	}{
+		{"seconds", Seconds(2), 83420 * time.Millisecond, "83.42 seconds"},
+		{"secondsWithoutDecimals", Seconds(0), 83420 * time.Millisecond, "83 seconds"},
+		{"short", Short(), 83420 * time.Millisecond, "1m23s"},
+		{"shortHours", Short(), time.Hour + 2*time.Minute + 3*time.Second, "1h2m3s"},
+		{"shortSubSecond", Short(), 450 * time.Millisecond, "450ms"},
+		{"clock", Clock(1), 83420 * time.Millisecond, "00:01:23.4"},
+		{"clockWithoutDecimals", Clock(0), 83420 * time.Millisecond, "00:01:23"},
+		{"clockRoundingUp", Clock(1), 59960 * time.Millisecond, "00:01:00.0"},
+		{"clockHours", Clock(0), 4523 * time.Second, "01:15:23"},
+		{"compactMilliseconds", Compact(), 450 * time.Millisecond, "450ms"},
+		{"compactSeconds", Compact(), 1234 * time.Millisecond, "1.2s"},
+		{"compactRoundingUp", Compact(), 59960 * time.Millisecond, "1m00s"},
+		{"compactMinutes", Compact(), 83420 * time.Millisecond, "1m23s"},
+		{"compactHours", Compact(), 4523 * time.Second, "1h15m"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if result := test.formatter(test.d); result != test.expected {
+				t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", test.expected, result)
+			}
+		})
+	}
+}
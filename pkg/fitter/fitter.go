@@ -0,0 +1,115 @@
+// Package fitter provides the line wrapping used by logboek streams: text is
+// wrapped at word boundaries to the given width, long words are split, and ANSI
+// colors active at a line break are reset before it and restored after it.
+package fitter
+
+import (
+	"io"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/werf/logboek/internal/stream/fitter"
+	"github.com/werf/logboek/pkg/types"
+)
+
+type Options struct {
+	// Width is the number of columns available for the text (at least 1).
+	Width int
+	// MarkWrappedLine ends each wrapped line with the "↵" sign.
+	MarkWrappedLine bool
+}
+
+func (o Options) width() int {
+	if o.Width < 1 {
+		return 1
+	}
+
+	return o.Width
+}
+
+// FitText wraps the complete text.
+func FitText(text string, options Options) string {
+	return fitter.FitText(text, &fitter.State{}, options.width(), options.MarkWrappedLine, false)
+}
+
+// AlignLine aligns a single line within the width, e.g. to center a banner or
+// to right-align a status column. Padding keeps the style of the line.
+func AlignLine(line string, width int, align types.TextAlign, padToWidth bool) string {
+	return fitter.AlignLine(line, width, align, padToWidth)
+}
+
+// StripANSI returns the text without ANSI escape sequences.
+func StripANSI(text string) string {
+	return fitter.StripANSI(text)
+}
+
+// VisibleWidth returns the number of terminal columns the text occupies when
+// wrapping: two columns for East Asian wide runes, none for combining marks,
+// one for other runes, escape sequences excluded.
+func VisibleWidth(text string) int {
+	return fitter.VisibleWidth(text)
+}
+
+// Writer wraps the data written to it and passes the result to the underlying
+// io.Writer. A line is written as soon as it is complete, so the incomplete
+// tail of the data is held until the next newline or Flush.
+type Writer struct {
+	w       io.Writer
+	options Options
+
+	state      fitter.State
+	incomplete []byte
+	mutex      sync.Mutex
+}
+
+func NewWriter(w io.Writer, options Options) *Writer {
+	return &Writer{
+		w:       w,
+		options: options,
+		state:   fitter.NewState(),
+	}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	data := append(w.incomplete, p...)
+	n := completeRunesLen(data)
+	w.incomplete = append([]byte(nil), data[n:]...)
+
+	if _, err := io.WriteString(w.w, fitter.FitText(string(data[:n]), &w.state, w.options.width(), w.options.MarkWrappedLine, true)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Flush writes the held incomplete line.
+func (w *Writer) Flush() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	data := string(w.incomplete)
+	w.incomplete = nil
+
+	_, err := io.WriteString(w.w, fitter.FitText(data, &w.state, w.options.width(), w.options.MarkWrappedLine, false))
+	return err
+}
+
+// completeRunesLen returns the length of the data without a trailing rune that
+// is cut in the middle, so multibyte characters split between writes are not
+// turned into replacement characters.
+func completeRunesLen(data []byte) int {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if utf8.FullRune(data[i:]) {
+				return len(data)
+			}
+
+			return i
+		}
+	}
+
+	return len(data)
+}
@@ -0,0 +1,103 @@
+package fitter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/werf/logboek/pkg/types"
+)
+
+func TestFitText(t *testing.T) {
+	result := FitText("foo bar data", Options{Width: 10})
+	if expected := "foo bar   \ndata"; result != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, result)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  Options
+		chunks   []string
+		expected string
+	}{
+		{
+			"heldUntilNewLine",
+			Options{Width: 10},
+			[]string{"foo ", "bar ", "data\nrest"},
+			"foo bar   \ndata\n",
+		},
+		{
+			"colorRestoredAfterWrap",
+			Options{Width: 10},
+			[]string{"\x1b[30m" + strings.Repeat("1", 11), "\x1b[0m\n"},
+			"\x1b[30m" + strings.Repeat("1", 10) + "\x1b[0m\n\x1b[30m1\x1b[0m\n",
+		},
+		{
+			"markWrappedLine",
+			Options{Width: 10, MarkWrappedLine: true},
+			[]string{strings.Repeat("1", 11) + "\n"},
+			"11111111 ↵\n111\n",
+		},
+		{
+			"runeSplitBetweenWrites",
+			Options{Width: 10},
+			[]string{"я"[:1], "я"[1:] + "\n"},
+			"я\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewWriter(&buf, test.options)
+			for _, chunk := range test.chunks {
+				if _, err := w.Write([]byte(chunk)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if result := buf.String(); result != test.expected {
+				t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestWriter_Flush(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, Options{Width: 10})
+
+	_, _ = w.Write([]byte("Continue? [y/N]"))
+	if buf.Len() != 0 {
+		t.Errorf("expected incomplete line to be held, got %q", buf.String())
+	}
+
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "Continue? \n[y/N]"; buf.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, buf.String())
+	}
+}
+
+func TestStripANSIAndVisibleWidth(t *testing.T) {
+	colored := "\x1b[1;32mпривет\x1b[0m"
+
+	if result := StripANSI(colored); result != "привет" {
+		t.Errorf("StripANSI:\n[EXPECTED]: %q\n[GOT]: %q", "привет", result)
+	}
+
+	if result := VisibleWidth(colored); result != 6 {
+		t.Errorf("VisibleWidth:\n[EXPECTED]: %d\n[GOT]: %d", 6, result)
+	}
+}
+
+func TestAlignLine(t *testing.T) {
+	result := AlignLine("\x1b[32mdone\x1b[0m", 10, types.AlignRight, false)
+	if expected := "\x1b[32m      done\x1b[0m"; result != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, result)
+	}
+}
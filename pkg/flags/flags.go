@@ -0,0 +1,108 @@
+// Package flags registers the standard logger command line flags and applies
+// the flags given by the user to a logger.
+package flags
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/werf/logboek/internal/settings"
+	"github.com/werf/logboek/pkg/level"
+	"github.com/werf/logboek/pkg/types"
+)
+
+type Flags struct {
+	fs *flag.FlagSet
+
+	Level                     level.Level
+	Verbose                   bool
+	Debug                     bool
+	Quiet                     bool
+	Color                     string
+	TerminalWidth             int
+	Format                    string
+	Pretty                    bool
+	TimePrefix                bool
+	GitlabCollapsibleSections bool
+}
+
+// Register registers the flags:
+//
+//	--log-level                        fatal, error, warn, default, info, debug, trace or a number
+//	--verbose, --debug, --quiet        shortcuts for the info, debug and error levels
+//	--log-color                        auto, on or off
+//	--log-terminal-width               width of the output
+//	--log-format                       pretty or plain, as LOGBOEK_FORMAT
+//	--log-pretty                       process borders and line wrapping (true by default)
+//	--log-time-prefix                  time prefix
+//	--log-gitlab-collapsible-sections  GitLab CI collapsible sections
+func Register(fs *flag.FlagSet) *Flags {
+	f := &Flags{fs: fs, Color: settings.ColorAuto, Format: settings.FormatPretty, Pretty: true}
+
+	fs.TextVar(&f.Level, "log-level", level.Default, "log level: fatal, error, warn, default, info, debug, trace or a number")
+	fs.BoolVar(&f.Verbose, "verbose", false, "enable verbose output (info log level)")
+	fs.BoolVar(&f.Debug, "debug", false, "enable debug output (debug log level)")
+	fs.BoolVar(&f.Quiet, "quiet", false, "print only errors (error log level)")
+	fs.StringVar(&f.Color, "log-color", f.Color, "colorize output: auto, on or off")
+	fs.IntVar(&f.TerminalWidth, "log-terminal-width", 0, "width of the output (detected by default)")
+	fs.StringVar(&f.Format, "log-format", f.Format, "output format: pretty or plain")
+	fs.BoolVar(&f.Pretty, "log-pretty", f.Pretty, "print process borders and wrap long lines")
+	fs.BoolVar(&f.TimePrefix, "log-time-prefix", false, "prefix each line with the time")
+	fs.BoolVar(&f.GitlabCollapsibleSections, "log-gitlab-collapsible-sections", false, "print processes as GitLab CI collapsible sections (enabled in GitLab CI by default)")
+
+	return f
+}
+
+// Apply applies the flags set on the command line to the logger, so the
+// settings of the other flags are kept. --log-level takes precedence over
+// --debug, --verbose and --quiet, and --log-format over --log-pretty.
+func (f *Flags) Apply(logger types.LoggerInterface) error {
+	set := map[string]bool{}
+	f.fs.Visit(func(fl *flag.Flag) {
+		set[fl.Name] = true
+	})
+
+	switch {
+	case set["log-level"]:
+		logger.SetAcceptedLevel(f.Level)
+	case set["debug"] && f.Debug:
+		logger.SetAcceptedLevel(level.Debug)
+	case set["verbose"] && f.Verbose:
+		logger.SetAcceptedLevel(level.Info)
+	case set["quiet"] && f.Quiet:
+		logger.SetAcceptedLevel(level.Error)
+	}
+
+	streams := logger.Streams()
+
+	if set["log-color"] {
+		if err := settings.SetColor(streams, f.Color); err != nil {
+			return fmt.Errorf("bad --log-color: %w", err)
+		}
+	}
+
+	if set["log-terminal-width"] {
+		if err := settings.SetWidth(streams, f.TerminalWidth); err != nil {
+			return fmt.Errorf("bad --log-terminal-width: %w", err)
+		}
+	}
+
+	switch {
+	case set["log-format"]:
+		if err := settings.SetFormat(streams, f.Format); err != nil {
+			return fmt.Errorf("bad --log-format: %w", err)
+		}
+	case set["log-pretty"]:
+		settings.SetPretty(streams, f.Pretty)
+	}
+
+	if set["log-time-prefix"] {
+		settings.SetPrefixTime(streams, f.TimePrefix)
+	}
+
+	if set["log-gitlab-collapsible-sections"] {
+		settings.SetGitlabCollapsibleSections(streams, f.GitlabCollapsibleSections)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package flags
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/werf/logboek/internal/logger"
+	"github.com/werf/logboek/pkg/level"
+)
+
+func TestFlags_Apply(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		expectedLevel level.Level
+		check         func(t *testing.T, l *logger.Logger)
+	}{
+		{"defaults", nil, level.Default, func(t *testing.T, l *logger.Logger) {
+			if !l.Streams().IsLogProcessBorderEnabled() || l.Streams().IsPrefixTimeEnabled() {
+				t.Errorf("expected default stream settings to be kept")
+			}
+		}},
+		{"logLevel", []string{"--log-level=trace", "--quiet"}, level.Trace, nil},
+		{"debug", []string{"--debug", "--verbose"}, level.Debug, nil},
+		{"verbose", []string{"--verbose"}, level.Info, nil},
+		{"quiet", []string{"--quiet"}, level.Error, nil},
+		{"plain", []string{"--log-pretty=false", "--log-color=off", "--log-terminal-width=60", "--log-time-prefix"}, level.Default, func(t *testing.T, l *logger.Logger) {
+			streams := l.Streams()
+			if streams.IsLogProcessBorderEnabled() || streams.IsLineWrappingEnabled() || streams.IsStyleEnabled() {
+				t.Errorf("expected plain output without style")
+			}
+
+			if streams.Width() != 60 || !streams.IsPrefixTimeEnabled() {
+				t.Errorf("expected width 60 and time prefix, got %d and %v", streams.Width(), streams.IsPrefixTimeEnabled())
+			}
+		}},
+		{"formatPlain", []string{"--log-format=plain", "--log-pretty"}, level.Default, func(t *testing.T, l *logger.Logger) {
+			if l.Streams().IsLogProcessBorderEnabled() || l.Streams().IsLineWrappingEnabled() {
+				t.Errorf("expected plain output")
+			}
+		}},
+		{"formatPretty", []string{"--log-pretty=false", "--log-format=pretty"}, level.Default, func(t *testing.T, l *logger.Logger) {
+			if !l.Streams().IsLogProcessBorderEnabled() || !l.Streams().IsLineWrappingEnabled() {
+				t.Errorf("expected pretty output")
+			}
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			f := Register(fs)
+			if err := fs.Parse(test.args); err != nil {
+				t.Fatal(err)
+			}
+
+			l := logger.NewLogger(&bytes.Buffer{}, &bytes.Buffer{})
+			if err := f.Apply(l); err != nil {
+				t.Fatal(err)
+			}
+
+			if l.AcceptedLevel() != test.expectedLevel {
+				t.Errorf("\n[EXPECTED]: %v\n[GOT]: %v", test.expectedLevel, l.AcceptedLevel())
+			}
+
+			if test.check != nil {
+				test.check(t, l)
+			}
+		})
+	}
+}
+
+func TestFlags_errors(t *testing.T) {
+	for _, args := range [][]string{{"--log-level=loud"}, {"--log-color=sometimes"}, {"--log-terminal-width=0"}, {"--log-format=json"}} {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		f := Register(fs)
+
+		err := fs.Parse(args)
+		if err == nil {
+			err = f.Apply(logger.NewLogger(&bytes.Buffer{}, &bytes.Buffer{}))
+		}
+
+		if err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+	}
+}
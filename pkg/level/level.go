@@ -1,13 +1,93 @@
 package level
 
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
 type Level int
 
+// Step is the distance between the predefined levels. The values in between
+// are left for custom channels, e.g. Info + Step/2 for a verbose channel shown
+// after Info and before Debug.
+const Step = 10
+
 const (
-	Error Level = iota - 2
-	Warn
-	Default // 0
-	Info
-	Debug
+	Fatal   Level = (iota - 3) * Step
+	Error         // -20
+	Warn          // -10
+	Default       // 0
+	Info          // 10
+	Debug         // 20
+	Trace         // 30
 )
 
-var List = []Level{Error, Warn, Default, Info, Debug}
+var List = []Level{Fatal, Error, Warn, Default, Info, Debug, Trace}
+
+var names = map[Level]string{
+	Fatal:   "fatal",
+	Error:   "error",
+	Warn:    "warn",
+	Default: "default",
+	Info:    "info",
+	Debug:   "debug",
+	Trace:   "trace",
+}
+
+var aliases = map[string]Level{
+	"warning": Warn,
+	"err":     Error,
+}
+
+// String returns the name of the predefined level or the number of a custom
+// level on the scale of the predefined levels, where Default is 0 and Info is
+// 1, e.g. 1.5 for Info + Step/2.
+func (l Level) String() string {
+	if name, ok := names[l]; ok {
+		return name
+	}
+
+	return strconv.FormatFloat(float64(l)/Step, 'f', -1, 64)
+}
+
+// ParseLevel parses the level name (case-insensitive) or number on the scale
+// of the predefined levels, e.g. 1 for Info or 1.5 for a level between Info and
+// Debug.
+func ParseLevel(value string) (Level, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	for lvl, name := range names {
+		if name == value {
+			return lvl, nil
+		}
+	}
+
+	if lvl, ok := aliases[value]; ok {
+		return lvl, nil
+	}
+
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		scaled := math.Round(number * Step)
+		if math.Abs(scaled-number*Step) < 1e-9 && math.Abs(scaled) <= math.MaxInt32 {
+			return Level(scaled), nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown log level %q: expected one of fatal, error, warn, default, info, debug, trace or a number", value)
+}
+
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+func (l *Level) UnmarshalText(text []byte) error {
+	lvl, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+
+	*l = lvl
+	return nil
+}
@@ -0,0 +1,63 @@
+package level
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Level
+	}{
+		{"info", Info},
+		{"DEBUG", Debug},
+		{" trace ", Trace},
+		{"warning", Warn},
+		{"15", Level(150)},
+		{"-3", Fatal},
+		{"1", Info},
+		{"1.5", Info + Step/2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			lvl, err := ParseLevel(test.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if lvl != test.expected {
+				t.Errorf("\n[EXPECTED]: %v\n[GOT]: %v", test.expected, lvl)
+			}
+		})
+	}
+
+	for _, value := range []string{"loud", "1.25", "NaN"} {
+		if _, err := ParseLevel(value); err == nil {
+			t.Errorf("expected error for level %q", value)
+		}
+	}
+}
+
+func TestLevel_text(t *testing.T) {
+	for _, lvl := range append(List, Level(15), Level(150), Level(-5)) {
+		data, err := json.Marshal(lvl)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var result Level
+		if err := json.Unmarshal(data, &result); err != nil {
+			t.Fatal(err)
+		}
+
+		if result != lvl {
+			t.Errorf("\n[EXPECTED]: %v\n[GOT]: %v (%s)", lvl, result, data)
+		}
+	}
+
+	if Info.String() != "info" || Level(15).String() != "1.5" || Level(150).String() != "15" {
+		t.Errorf("unexpected level names: %s, %s, %s", Info, Level(15), Level(150))
+	}
+}
@@ -0,0 +1,285 @@
+// Package logboektest helps testing tools built on logboek: a logger writing
+// into the test log and a recorder capturing the output as plain text with
+// deterministic timings, with assertion helpers.
+package logboektest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/werf/logboek/internal/logger"
+	"github.com/werf/logboek/internal/stream/fitter"
+	"github.com/werf/logboek/pkg/clock"
+	"github.com/werf/logboek/pkg/types"
+)
+
+// Width is the fixed width of the test loggers.
+const Width = 100
+
+// UpdateGoldenEnv is the environment variable which makes RequireGolden write
+// the golden files instead of comparing them, e.g. LOGBOEKTEST_UPDATE=1.
+const UpdateGoldenEnv = "LOGBOEKTEST_UPDATE"
+
+// NewLogger returns a logger writing each line of its output into t.Log.
+func NewLogger(t testing.TB) types.LoggerInterface {
+	w := &testLogWriter{t: t}
+	t.Cleanup(w.flush)
+
+	l := logger.NewLogger(w, w)
+	configureLogger(l)
+
+	return l
+}
+
+type testLogWriter struct {
+	t     testing.TB
+	line  []byte
+	mutex sync.Mutex
+}
+
+func (w *testLogWriter) Write(data []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.line = append(w.line, data...)
+	for {
+		i := bytes.IndexByte(w.line, '\n')
+		if i == -1 {
+			break
+		}
+
+		w.t.Log(string(w.line[:i]))
+		w.line = w.line[i+1:]
+	}
+
+	return len(data), nil
+}
+
+func (w *testLogWriter) flush() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if len(w.line) != 0 {
+		w.t.Log(string(w.line))
+		w.line = nil
+	}
+}
+
+// Recorder captures the output of its logger. The output is plain text: styles
+// are disabled and the width is fixed. The logger uses a fake clock, so elapsed
+// times are zero unless the clock is advanced.
+type Recorder struct {
+	logger *logger.Logger
+	clock  *clock.Fake
+
+	mutex  sync.Mutex
+	output bytes.Buffer
+	out    bytes.Buffer
+	err    bytes.Buffer
+}
+
+func NewRecorder() *Recorder {
+	r := &Recorder{clock: clock.NewFake(recorderStartTime)}
+	r.logger = logger.NewLogger(recorderWriter{r, &r.out}, recorderWriter{r, &r.err})
+	r.logger.Streams().SetClock(r.clock)
+	configureLogger(r.logger)
+
+	return r
+}
+
+// recorderStartTime is the time of the recorder clock when it is created.
+var recorderStartTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func configureLogger(l *logger.Logger) {
+	l.Streams().DisableStyle()
+	l.Streams().SetWidth(Width)
+}
+
+func (r *Recorder) Logger() types.LoggerInterface {
+	return r.logger
+}
+
+func (r *Recorder) Clock() *clock.Fake {
+	return r.clock
+}
+
+// Output returns the output of both streams in the order it was written.
+func (r *Recorder) Output() string {
+	return r.normalized(&r.output)
+}
+
+// OutStream returns the output of the Default, Info, Debug and Trace channels.
+func (r *Recorder) OutStream() string {
+	return r.normalized(&r.out)
+}
+
+// ErrStream returns the output of the Fatal, Error and Warn channels.
+func (r *Recorder) ErrStream() string {
+	return r.normalized(&r.err)
+}
+
+func (r *Recorder) normalized(buf *bytes.Buffer) string {
+	r.logger.Flush()
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return fitter.StripANSI(buf.String())
+}
+
+type recorderWriter struct {
+	recorder *Recorder
+	buf      *bytes.Buffer
+}
+
+func (w recorderWriter) Write(data []byte) (int, error) {
+	w.recorder.mutex.Lock()
+	defer w.recorder.mutex.Unlock()
+
+	w.recorder.output.Write(data)
+	return w.buf.Write(data)
+}
+
+// RequireProcess checks that the process with the title has been logged and
+// has succeeded.
+func RequireProcess(t testing.TB, r *Recorder, title string) {
+	t.Helper()
+
+	switch processResult(r.Output(), title) {
+	case processNotFound:
+		t.Fatalf("process %q has not been logged:\n%s", title, r.Output())
+	case processNotFinished:
+		t.Fatalf("process %q has not been finished:\n%s", title, r.Output())
+	case processFailed:
+		t.Fatalf("process %q has failed:\n%s", title, r.Output())
+	}
+}
+
+// RequireProcessFailed checks that the process with the title has been logged
+// and has failed.
+func RequireProcessFailed(t testing.TB, r *Recorder, title string) {
+	t.Helper()
+
+	if result := processResult(r.Output(), title); result != processFailed {
+		t.Fatalf("process %q has not failed:\n%s", title, r.Output())
+	}
+}
+
+// RequireNoErrors checks that nothing has been written into the Error and Warn
+// channels and no process has failed.
+func RequireNoErrors(t testing.TB, r *Recorder) {
+	t.Helper()
+
+	if errStream := r.ErrStream(); errStream != "" {
+		t.Fatalf("unexpected errors:\n%s", errStream)
+	}
+
+	for _, line := range strings.Split(r.Output(), "\n") {
+		if strings.Contains(line, ") FAILED") || strings.HasSuffix(line, " FAILED") || strings.Contains(line, " FAILED: ") {
+			t.Fatalf("unexpected failed process:\n%s", line)
+		}
+	}
+}
+
+// RequireGolden compares the output with the golden file. The golden file is
+// written instead if the UpdateGoldenEnv environment variable is set to true.
+func RequireGolden(t testing.TB, r *Recorder, path string) {
+	t.Helper()
+
+	output := r.Output()
+
+	if update, _ := strconv.ParseBool(os.Getenv(UpdateGoldenEnv)); update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(output), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		return
+	}
+
+	golden, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unable to read golden file (run tests with %s=1 to create it): %s", UpdateGoldenEnv, err)
+	}
+
+	if string(golden) != output {
+		t.Fatalf("output does not match golden file %s:\n[EXPECTED]:\n%s\n[GOT]:\n%s", path, golden, output)
+	}
+}
+
+type processResultType int
+
+const (
+	processNotFound processResultType = iota
+	processNotFinished
+	processSucceeded
+	processFailed
+)
+
+var (
+	borderRegexp      = regexp.MustCompile(`^[│ ]*`)
+	elapsedTimeRegexp = regexp.MustCompile(`^\([^()]*\)`)
+)
+
+func processResult(output, title string) processResultType {
+	result := processNotFound
+
+	for _, line := range strings.Split(output, "\n") {
+		line = borderRegexp.ReplaceAllString(line, "")
+
+		if line == "┌ "+title {
+			result = processNotFinished
+			continue
+		}
+
+		for _, footerPrefix := range []string{"└ " + title, title + " ..."} {
+			if status, ok := processFooterStatus(line, footerPrefix); ok {
+				if isFailedProcessStatus(status) {
+					return processFailed
+				}
+				return processSucceeded
+			}
+		}
+	}
+
+	return result
+}
+
+// processFooterStatus returns the part of the footer line after the title and
+// the elapsed time, if any: the failure status, the reason and the fields.
+func processFooterStatus(line, footerPrefix string) (string, bool) {
+	if !strings.HasPrefix(line, footerPrefix) {
+		return "", false
+	}
+
+	status := line[len(footerPrefix):]
+	if status != "" && status[0] != ' ' {
+		return "", false
+	}
+
+	status = strings.TrimLeft(status, " ")
+	if elapsedTime := elapsedTimeRegexp.FindString(status); elapsedTime != "" {
+		status = strings.TrimLeft(status[len(elapsedTime):], " ")
+	}
+
+	// The rest of the title of another process, e.g. "Build image" for "Build".
+	if status != "" && !strings.HasPrefix(status, "FAILED") && !strings.Contains(strings.Fields(status)[0], "=") {
+		return "", false
+	}
+
+	return status, true
+}
+
+func isFailedProcessStatus(status string) bool {
+	return status == "FAILED" || strings.HasPrefix(status, "FAILED:") || strings.HasPrefix(status, "FAILED ")
+}
@@ -0,0 +1,150 @@
+package logboektest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/werf/logboek/pkg/types"
+)
+
+func record() *Recorder {
+	r := NewRecorder()
+	l := r.Logger()
+
+	l.LogProcess("Building").Do(func() {
+		l.LogLn("compiling")
+		r.Clock().Advance(1500 * time.Millisecond)
+		_ = l.LogProcess("Testing").DoError(func() error {
+			l.Warn().LogLn("flaky test")
+			return errors.New("failed")
+		})
+	})
+
+	_ = l.LogProcessInline("Cleaning").DoError(func() error { return nil })
+
+	return r
+}
+
+func TestRequireProcess(t *testing.T) {
+	r := record()
+
+	RequireProcess(t, r, "Building")
+	RequireProcess(t, r, "Cleaning")
+	RequireProcessFailed(t, r, "Testing")
+
+	for _, test := range []struct {
+		name     string
+		title    string
+		expected processResultType
+	}{
+		{"succeeded", "Building", processSucceeded},
+		{"inline", "Cleaning", processSucceeded},
+		{"failed", "Testing", processFailed},
+		{"notFound", "Deploying", processNotFound},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if result := processResult(r.Output(), test.title); result != test.expected {
+				t.Errorf("\n[EXPECTED]: %v\n[GOT]: %v", test.expected, result)
+			}
+		})
+	}
+}
+
+func TestRequireProcess_withoutElapsedTime(t *testing.T) {
+	r := NewRecorder()
+	l := r.Logger()
+
+	withoutElapsedTime := func(failureReason string) func(types.LogProcessOptionsInterface) {
+		return func(options types.LogProcessOptionsInterface) {
+			options.WithoutElapsedTime()
+			options.FailureReasonFunc(func(error) string { return failureReason })
+		}
+	}
+
+	_ = l.LogProcess("Testing").Options(withoutElapsedTime("exit (1)")).DoError(func() error { return errors.New("failed") })
+	_ = l.LogProcess("Linting").Options(withoutElapsedTime("")).DoError(func() error { return errors.New("failed") })
+	_ = l.LogProcess("Build image").Options(func(options types.LogProcessOptionsInterface) {
+		options.WithoutElapsedTime()
+		options.Fields("tag", "latest")
+	}).DoError(func() error { return nil })
+	l.Streams().SetElapsedTimeThreshold(time.Second)
+	_ = l.LogProcessInline("Cleaning").DoError(func() error { return errors.New("failed") })
+
+	for _, test := range []struct {
+		name     string
+		title    string
+		expected processResultType
+	}{
+		{"failureReason", "Testing", processFailed},
+		{"failed", "Linting", processFailed},
+		{"fields", "Build image", processSucceeded},
+		{"titlePrefix", "Build", processNotFound},
+		{"inlineFailed", "Cleaning", processFailed},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if result := processResult(r.Output(), test.title); result != test.expected {
+				t.Errorf("\n[EXPECTED]: %v\n[GOT]: %v\n%s", test.expected, result, r.Output())
+			}
+		})
+	}
+}
+
+func TestRequireNoErrors(t *testing.T) {
+	r := NewRecorder()
+	r.Logger().LogProcess("Building").Do(func() {
+		r.Logger().LogLn("compiling")
+	})
+
+	RequireNoErrors(t, r)
+
+	if errStream := record().ErrStream(); errStream != "│ │ flaky test\n" {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", "│ │ flaky test\n", errStream)
+	}
+}
+
+func TestRequireGolden(t *testing.T) {
+	RequireGolden(t, record(), "testdata/record.golden")
+}
+
+type recordingTB struct {
+	testing.TB
+	logs     []string
+	cleanups []func()
+}
+
+func (t *recordingTB) Log(args ...interface{}) {
+	t.logs = append(t.logs, fmt.Sprint(args...))
+}
+
+func (t *recordingTB) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func TestNewLogger(t *testing.T) {
+	tb := &recordingTB{TB: t}
+
+	l := NewLogger(tb)
+	l.LogProcess("Building").Options(func(options types.LogProcessOptionsInterface) {
+		options.WithoutElapsedTime()
+	}).Do(func() {
+		l.LogLn("compiling")
+	})
+	l.LogF("incomplete line")
+
+	expected := []string{"┌ Building", "│ compiling", "└ Building", ""}
+	if !reflect.DeepEqual(expected, tb.logs) {
+		t.Fatalf("\n[EXPECTED]: %q\n[GOT]: %q", expected, tb.logs)
+	}
+
+	for _, f := range tb.cleanups {
+		f()
+	}
+
+	expected = append(expected, "incomplete line")
+	if !reflect.DeepEqual(expected, tb.logs) {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, tb.logs)
+	}
+}
@@ -0,0 +1,138 @@
+// Package stdlog routes the output of the standard library log package into a
+// logboek logger, so log.Printf lines respect the indentation, borders and the
+// accepted level of the logger.
+package stdlog
+
+import (
+	"io"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/werf/logboek/pkg/level"
+	"github.com/werf/logboek/pkg/types"
+)
+
+type Options struct {
+	// Level is the channel of the lines without a detected level (the Default
+	// channel by default).
+	Level level.Level
+	// DetectLevel routes lines starting with level markers like "[WARN]" or
+	// "ERROR:", or with the "level=debug" key, to the matching channel.
+	DetectLevel bool
+}
+
+// New returns a standard library logger writing into the logboek logger.
+func New(logger types.LoggerInterface, options Options, prefix string, flag int) *log.Logger {
+	return log.New(&writer{logger: logger, options: options, prefix: prefix}, prefix, flag)
+}
+
+// NewWriter returns a writer for log.SetOutput. Each write of the log package
+// is a single log entry.
+func NewWriter(logger types.LoggerInterface, options Options) io.Writer {
+	return &writer{logger: logger, options: options}
+}
+
+type writer struct {
+	logger  types.LoggerInterface
+	options Options
+	// prefix is the prefix of the log package logger, skipped before the
+	// level marker.
+	prefix string
+}
+
+func (w *writer) Write(data []byte) (int, error) {
+	entry := string(data)
+	if !strings.HasSuffix(entry, "\n") {
+		entry += "\n"
+	}
+
+	lvl := w.options.Level
+	if w.options.DetectLevel {
+		if detectedLvl, ok := detectLevel(entry, w.prefix); ok {
+			lvl = detectedLvl
+		}
+	}
+
+	managerByLevel(w.logger, lvl).LogF("%s", entry)
+
+	return len(data), nil
+}
+
+func managerByLevel(logger types.LoggerInterface, lvl level.Level) types.ManagerInterface {
+	switch lvl {
+	case level.Error:
+		return logger.Error()
+	case level.Warn:
+		return logger.Warn()
+	case level.Info:
+		return logger.Info()
+	case level.Debug:
+		return logger.Debug()
+	case level.Trace:
+		return logger.Trace()
+	default:
+		return logger.Default()
+	}
+}
+
+var (
+	// logHeaderRegexp matches the date, time and file header of the log package.
+	logHeaderRegexp       = regexp.MustCompile(`^(?:\d{4}/\d{2}/\d{2} )?(?:\d{2}:\d{2}:\d{2}(?:\.\d+)? )?(?:[^\s:]+\.go:\d+: )?`)
+	bracketedMarkerRegexp = regexp.MustCompile(`^\[(\w+)\]\s*`)
+	colonMarkerRegexp     = regexp.MustCompile(`^(\w+):`)
+	levelKeyRegexp        = regexp.MustCompile(`(?i)(?:^|\s)level=["']?(\w+)`)
+)
+
+var levelByMarker = map[string]level.Level{
+	"error":   level.Error,
+	"err":     level.Error,
+	"fatal":   level.Error,
+	"panic":   level.Error,
+	"warn":    level.Warn,
+	"warning": level.Warn,
+	"info":    level.Info,
+	"debug":   level.Debug,
+	"trace":   level.Trace,
+}
+
+// DetectLevel returns the level of the marker at the start of the message,
+// after the header of the log package: "[WARN]" (unknown bracketed markers like
+// "[main]" are skipped) or "ERROR:". Otherwise, the level of the "level=debug"
+// key is returned. Markers are case-insensitive.
+func DetectLevel(line string) (level.Level, bool) {
+	return detectLevel(line, "")
+}
+
+func detectLevel(line, prefix string) (level.Level, bool) {
+	msg := strings.TrimPrefix(line, prefix)
+	msg = msg[len(logHeaderRegexp.FindString(msg)):]
+	msg = strings.TrimPrefix(msg, prefix)
+
+	for {
+		match := bracketedMarkerRegexp.FindStringSubmatch(msg)
+		if match == nil {
+			break
+		}
+
+		if lvl, ok := levelByMarker[strings.ToLower(match[1])]; ok {
+			return lvl, true
+		}
+
+		msg = msg[len(match[0]):]
+	}
+
+	if match := colonMarkerRegexp.FindStringSubmatch(msg); match != nil {
+		if lvl, ok := levelByMarker[strings.ToLower(match[1])]; ok {
+			return lvl, true
+		}
+	}
+
+	if match := levelKeyRegexp.FindStringSubmatch(line); match != nil {
+		if lvl, ok := levelByMarker[strings.ToLower(match[1])]; ok {
+			return lvl, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,87 @@
+package stdlog
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/werf/logboek/internal/logger"
+	"github.com/werf/logboek/pkg/level"
+)
+
+func TestDetectLevel(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		expected   level.Level
+		expectedOk bool
+	}{
+		{"bracketed", "2023/01/02 10:00:00 [WARN] disk is almost full", level.Warn, true},
+		{"colon", "ERROR: connection refused", level.Error, true},
+		{"keyValue", `time=10:00 level=debug msg="cache miss"`, level.Debug, true},
+		{"quotedKeyValue", `level="info" msg=started`, level.Info, true},
+		{"lowercase", "[warning] deprecated option", level.Warn, true},
+		{"firstMarkerWins", "[INFO] retrying after error: timeout", level.Info, true},
+		{"unknownMarkerSkipped", "[main] ERROR: failed", level.Error, true},
+		{"shortFileHeader", "10:00:00 main.go:12: WARN: retrying", level.Warn, true},
+		{"midLineColon", "request failed with error: timeout", 0, false},
+		{"midLineDebugColon", "2023/01/02 10:00:00 cache debug info: x", 0, false},
+		{"midLineBracketed", "retrying [ERROR] later", 0, false},
+		{"none", "server started on :8080", 0, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			lvl, ok := DetectLevel(test.line)
+			if lvl != test.expected || ok != test.expectedOk {
+				t.Errorf("\n[EXPECTED]: %v %v\n[GOT]: %v %v", test.expected, test.expectedOk, lvl, ok)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := logger.NewLogger(&out, &errOut)
+	l.Streams().DisableStyle()
+
+	stdLogger := New(l, Options{DetectLevel: true}, "", 0)
+	l.LogProcess("process").Do(func() {
+		stdLogger.Print("started")
+		stdLogger.Print("[WARN] slow response")
+		stdLogger.Print("level=debug msg=ignored")
+	})
+
+	if expected := "┌ process\n│ started\n"; !bytes.HasPrefix(out.Bytes(), []byte(expected)) {
+		t.Errorf("\n[EXPECTED]: %q prefix\n[GOT]: %q", expected, out.String())
+	}
+
+	if bytes.Contains(out.Bytes(), []byte("ignored")) {
+		t.Errorf("debug line is not accepted by default: %q", out.String())
+	}
+
+	if expected := "│ [WARN] slow response\n"; errOut.String() != expected {
+		t.Errorf("\n[EXPECTED]: %q\n[GOT]: %q", expected, errOut.String())
+	}
+}
+
+func TestNew_prefix(t *testing.T) {
+	var out, errOut bytes.Buffer
+	l := logger.NewLogger(&out, &errOut)
+	l.Streams().DisableStyle()
+
+	for _, flag := range []int{0, log.Lmsgprefix | log.Ltime} {
+		stdLogger := New(l, Options{DetectLevel: true}, "app: ", flag)
+		stdLogger.Print("ERROR: connection refused")
+		stdLogger.Print("request failed with error: timeout")
+	}
+
+	if lines := strings.Count(errOut.String(), "ERROR: connection refused\n"); lines != 2 {
+		t.Errorf("expected 2 error lines, got %q", errOut.String())
+	}
+
+	if lines := strings.Count(out.String(), "request failed with error: timeout\n"); lines != 2 {
+		t.Errorf("expected 2 default lines, got %q", out.String())
+	}
+}
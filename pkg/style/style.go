@@ -9,6 +9,7 @@ const (
 	HighlightName = "logboek_highlight"
 	DetailsName   = "logboek_details"
 	NoneName      = "logboek_none"
+	FieldsName    = "logboek_fields"
 
 	// internal styles
 	ProcessFailName = "logboek_process_fail"
@@ -19,6 +20,7 @@ func init() {
 		HighlightName:   {color.Bold},
 		DetailsName:     {color.FgBlue, color.Bold},
 		NoneName:        {},
+		FieldsName:      {color.OpFuzzy},
 		ProcessFailName: {color.FgRed, color.Bold},
 	}
 
@@ -35,6 +37,10 @@ func Highlight() color.Style {
 	return color.GetStyle(HighlightName)
 }
 
+func Fields() color.Style {
+	return color.GetStyle(FieldsName)
+}
+
 func None() color.Style {
 	return color.GetStyle(NoneName)
 }
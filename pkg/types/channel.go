@@ -0,0 +1,25 @@
+package types
+
+import "github.com/gookit/color"
+
+// StreamRoute is the stream the messages of a channel are written to.
+type StreamRoute int
+
+const (
+	// DefaultStreamRoute writes Warn and more severe levels to the err stream
+	// and the other levels to the out stream.
+	DefaultStreamRoute StreamRoute = iota
+	OutStreamRoute
+	ErrStreamRoute
+	// BothStreamsRoute writes the messages to both streams. Log processes,
+	// blocks and proxy stream data are written only to the default stream of
+	// the level, the other stream gets the copies of the messages.
+	BothStreamsRoute
+)
+
+type ChannelOptions struct {
+	Style      color.Style
+	Label      string
+	LabelStyle color.Style
+	Route      StreamRoute
+}
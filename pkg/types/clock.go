@@ -0,0 +1,9 @@
+package types
+
+import "time"
+
+// Clock is the source of the current time for elapsed times, prefix
+// timestamps and GitLab section markers.
+type Clock interface {
+	Now() time.Time
+}
@@ -0,0 +1,21 @@
+package types
+
+import (
+	"context"
+
+	"github.com/gookit/color"
+)
+
+type LogCommandInterface interface {
+	Options(func(options LogCommandOptionsInterface)) LogCommandInterface
+	Run(ctx context.Context) error
+}
+
+type LogCommandOptionsInterface interface {
+	Title(headerOrFormat string, a ...interface{})
+	StdoutTag(value string, style color.Style)
+	StderrTag(value string, style color.Style)
+	StdoutStyle(style color.Style)
+	StderrStyle(style color.Style)
+	ProcessOptions(func(options LogProcessOptionsInterface))
+}
@@ -0,0 +1,6 @@
+package types
+
+import "time"
+
+// DurationFormatter formats elapsed times of processes and the duration prefix.
+type DurationFormatter func(d time.Duration) string
@@ -1,8 +1,23 @@
 package types
 
+type TextAlign int
+
+const (
+	AlignLeft TextAlign = iota
+	AlignCenter
+	AlignRight
+	// AlignJustify stretches every wrapped line to the full width; the last
+	// line of a paragraph is aligned to the left.
+	AlignJustify
+)
+
 type FitTextOptions struct {
 	ExtraIndentWidth int
 	Width            int
 	MaxWidth         int
 	MarkWrappedLine  bool
+	Align            TextAlign
+	// PadToWidth pads every line with spaces to the full width, so a styled
+	// background fills the row.
+	PadToWidth bool
 }
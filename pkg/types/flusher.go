@@ -0,0 +1,9 @@
+package types
+
+// Flusher writes the incomplete lines of data written to the streams. It is
+// implemented by the loggers and managers of the package; it is not a part of
+// LoggerInterface and ManagerInterface to keep their other implementations
+// valid.
+type Flusher interface {
+	Flush()
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"io"
+	"os/exec"
 
 	"github.com/gookit/color"
 
@@ -11,11 +12,25 @@ import (
 type LoggerInterface interface {
 	ManagerLogInterface
 
+	Fatal() ManagerInterface
 	Error() ManagerInterface
 	Warn() ManagerInterface
 	Default() ManagerInterface
 	Info() ManagerInterface
 	Debug() ManagerInterface
+	Trace() ManagerInterface
+
+	EnableLevelLabels()
+	DisableLevelLabels()
+
+	SetExitFunc(f func(code int))
+	RegisterChannel(name string, lvl level.Level, options ChannelOptions) ManagerInterface
+	Channel(name string) (ManagerInterface, bool)
+
+	With(keysAndValues ...interface{}) LoggerInterface
+	Component(name string) LoggerInterface
+	ComponentName() string
+	SetComponentLevels(spec string) error
 
 	FitText(text string, options FitTextOptions) string
 	Colorize(style color.Style, a ...interface{}) string
@@ -25,11 +40,16 @@ type LoggerInterface interface {
 	AcceptedLevel() level.Level
 	SetAcceptedLevel(lvl level.Level)
 	IsAcceptedLevel(lvl level.Level) bool
+	DoWithAcceptedLevel(lvl level.Level, f func())
+	DoErrorWithAcceptedLevel(lvl level.Level, f func() error) error
 
 	Streams() StreamsInterface
 	OutStream() io.Writer
 	ErrStream() io.Writer
 
+	LogCommand(cmd *exec.Cmd) LogCommandInterface
+	CaptureStdFDs() (func() error, error)
+
 	NewSubLogger(outStream, errStream io.Writer) LoggerInterface
 	GetStreamsSettingsFrom(l LoggerInterface)
 
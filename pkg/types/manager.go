@@ -4,6 +4,8 @@ import (
 	"io"
 
 	"github.com/gookit/color"
+
+	"github.com/werf/logboek/pkg/level"
 )
 
 type ManagerInterface interface {
@@ -12,7 +14,17 @@ type ManagerInterface interface {
 
 	SetStyle(style color.Style)
 	Style() color.Style
+	SetLabel(label string)
+	Label() string
+	SetLabelStyle(style color.Style)
+	LabelStyle() color.Style
+
+	SetStreamRoute(route StreamRoute)
+	StreamRoute() StreamRoute
+
+	With(keysAndValues ...interface{}) ManagerInterface
 
+	Level() level.Level
 	IsAccepted() bool
 }
 
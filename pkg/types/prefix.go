@@ -0,0 +1,24 @@
+package types
+
+import (
+	"time"
+
+	"github.com/werf/logboek/pkg/level"
+)
+
+// PrefixContext describes the line the prefix is rendered for.
+type PrefixContext struct {
+	// Level is the level of the manager writing the line.
+	Level level.Level
+	// LineNumber is the number of the line in the logger output, from 1.
+	LineNumber int
+	// Elapsed is the time since the prefix duration start time.
+	Elapsed time.Duration
+	// ProcessDepth is the number of active log processes.
+	ProcessDepth int
+}
+
+// PrefixFunc returns a prefix segment. Empty segments are skipped. The function
+// is called once per line while the logger streams are locked, so it must not
+// log.
+type PrefixFunc func(ctx PrefixContext) string
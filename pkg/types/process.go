@@ -2,6 +2,8 @@ package types
 
 import (
 	"github.com/gookit/color"
+
+	"github.com/werf/logboek/pkg/level"
 )
 
 type LogBlockInterface interface {
@@ -18,6 +20,8 @@ type LogBlockOptionsInterface interface {
 	WithIndent()
 	WithoutLogOptionalLn()
 	Style(color.Style)
+	AcceptedLevel(lvl level.Level)
+	Fields(keysAndValues ...interface{})
 }
 
 type LogProcessInlineInterface interface {
@@ -32,6 +36,7 @@ type LogProcessInlineOptionsInterface interface {
 	DisableIfLevelNotAccepted()
 	Mute()
 	Style(color.Style)
+	Fields(keysAndValues ...interface{})
 }
 
 type LogProcessInterface interface {
@@ -54,5 +59,8 @@ type LogProcessOptionsInterface interface {
 	WithoutElapsedTime()
 	InfoSectionFunc(func(err error))
 	SuccessInfoSectionFunc(func())
+	FailureReasonFunc(func(err error) string)
+	Fields(keysAndValues ...interface{})
 	Style(color.Style)
+	AcceptedLevel(lvl level.Level)
 }
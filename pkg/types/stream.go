@@ -1,6 +1,8 @@
 package types
 
 import (
+	"time"
+
 	"github.com/gookit/color"
 )
 
@@ -23,14 +25,30 @@ type StreamsInterface interface {
 	DisableProxyStreamDataFormatting()
 	IsProxyStreamDataFormattingEnabled() bool
 
+	EnableProxyStreamDataStyleStripping()
+	DisableProxyStreamDataStyleStripping()
+	IsProxyStreamDataStyleStrippingEnabled() bool
+
+	EnableProxyStreamDataCarriageReturnCollapsing()
+	DisableProxyStreamDataCarriageReturnCollapsing()
+	IsProxyStreamDataCarriageReturnCollapsingEnabled() bool
+
+	SetProxyStreamDataFlushTimeout(timeout time.Duration)
+	ProxyStreamDataFlushTimeout() time.Duration
+
 	EnableLineWrapping()
 	DisableLineWrapping()
 	IsLineWrappingEnabled() bool
 
 	EnableStyle()
 	DisableStyle()
+	ResetStyle()
 	IsStyleEnabled() bool
 
+	EnableControlCharsSanitizing()
+	DisableControlCharsSanitizing()
+	IsControlCharsSanitizingEnabled() bool
+
 	Width() int
 	SetWidth(value int)
 	ContentWidth() int
@@ -49,21 +67,42 @@ type StreamsInterface interface {
 	SetTag(value string)
 	SetTagStyle(style color.Style)
 	SetTagWithStyle(value string, style color.Style)
+	SetTagSeparator(separator string)
+	EnableTagAutoStyle()
+	DisableTagAutoStyle()
+	IsTagAutoStyleEnabled() bool
+	EnableTagAlignment()
+	DisableTagAlignment()
+	IsTagAlignmentEnabled() bool
 	ResetTag()
 
+	EnableCallerLocation()
+	DisableCallerLocation()
+	IsCallerLocationEnabled() bool
+
 	EnablePrefixDuration()
 	DisablePrefixDuration()
 	IsPrefixDurationEnabled() bool
 	ResetPrefixDurationStartTime()
+	SetPrefixDurationFormatter(formatter DurationFormatter)
 	SetPrefixTimeFormat(format string)
+	SetElapsedTimeFormatter(formatter DurationFormatter)
+	SetElapsedTimeThreshold(threshold time.Duration)
+	SetClock(clock Clock)
+	Clock() Clock
 	EnablePrefixTime()
 	DisablePrefixTime()
 	IsPrefixTimeEnabled() bool
 	SetPrefix(value string)
+	SetPrefixFunc(f PrefixFunc)
+	AppendPrefixFunc(f PrefixFunc)
 	SetPrefixStyle(style color.Style)
 	DisablePrefix()
 
 	EnableLogProcessBorder()
 	DisableLogProcessBorder()
 	IsLogProcessBorderEnabled() bool
+	EnableLogProcessStatusAlignment()
+	DisableLogProcessStatusAlignment()
+	IsLogProcessStatusAlignmentEnabled() bool
 }